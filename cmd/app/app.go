@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,20 +14,25 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	handler "github.com/ruziba3vich/music_lib/internal/http"
+	"github.com/ruziba3vich/music_lib/internal/models"
 	redisservice "github.com/ruziba3vich/music_lib/internal/redis_service"
 	"github.com/ruziba3vich/music_lib/internal/service"
 	"github.com/ruziba3vich/music_lib/internal/storage"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
 	"github.com/ruziba3vich/music_lib/pkg/config"
+	"github.com/ruziba3vich/music_lib/pkg/cursor"
+	"github.com/ruziba3vich/music_lib/pkg/ratelimit"
+	"github.com/ruziba3vich/music_lib/pkg/songinfo"
 )
 
-// Run initializes and starts the application with graceful shutdown
-func Run(logger *log.Logger) error {
-	// Load configuration
-	cfg := config.LoadConfig()
+// NewService connects to the database and Redis and builds the service layer
+// on top of them. The Redis client is returned for the parts of the app that
+// use it directly.
+func NewService(cfg *config.Config, logger *log.Logger) (*service.Service, *redis.Client, error) {
 	// Connect to database
 	db, err := storage.GetDBConnection(cfg)
 	if err != nil {
-		logger.Fatalf("Failed to connect to database: %v", err)
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	client := redis.NewClient(&redis.Options{
@@ -36,22 +42,96 @@ func Run(logger *log.Logger) error {
 	})
 
 	// Test connection
-	ctx := context.Background()
-	if err := client.Ping(ctx).Err(); err != nil {
-		logger.Fatalf("Could not connect to Redis: %v", err)
-		return err
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, nil, fmt.Errorf("could not connect to Redis: %w", err)
 	}
 
 	redisservice := redisservice.NewRedisService(client, cfg)
 
+	// Pagination cursors are signed so clients cannot forge positions
+	cursorSecret := []byte(cfg.CursorSecret)
+	if len(cursorSecret) == 0 {
+		// Only in development or in the CLI, where cursors never leave the process
+		logger.Println("CURSOR_SECRET is not set, using a random secret; cursors will not survive restarts")
+		cursorSecret = make([]byte, 32)
+		if _, err := rand.Read(cursorSecret); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Initialize storage
-	store := storage.NewStorage(db, redisservice)
+	store := storage.NewStorage(db, redisservice, cursor.NewCodec(cursorSecret))
+
+	// Initialize external song info client
+	var songInfo *songinfo.Client
+	if cfg.ExternalAPI != "" {
+		songInfo = songinfo.NewClient(
+			cfg.ExternalAPI,
+			time.Duration(cfg.ExternalAPITimeout)*time.Second,
+			cfg.ExternalAPIRetries,
+			time.Duration(cfg.ExternalAPIBackoff)*time.Millisecond,
+		)
+	}
 
 	// Initialize service layer
-	service := service.NewService(store, logger)
+	return service.NewService(store, songInfo, logger), client, nil
+}
+
+// Run initializes and starts the application with graceful shutdown
+func Run(logger *log.Logger) error {
+	// Load configuration
+	cfg := config.LoadConfig()
+	if err := requireSecrets(cfg); err != nil {
+		return err
+	}
+
+	service, client, err := NewService(cfg, logger)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	// Registration only creates viewers, so the first admin comes from the configuration
+	if cfg.AdminUsername != "" {
+		credentials := models.Credentials{Username: cfg.AdminUsername, Password: cfg.AdminPassword}
+		if err := service.BootstrapAdmin(context.Background(), credentials); err != nil {
+			return err
+		}
+	}
+
+	// Access and refresh tokens are signed JWTs
+	jwtSecret := []byte(cfg.JWTSecret)
+	if len(jwtSecret) == 0 {
+		// Only in development
+		logger.Println("JWT_SECRET is not set, using a random secret; tokens will not survive restarts")
+		jwtSecret = make([]byte, 32)
+		if _, err := rand.Read(jwtSecret); err != nil {
+			return err
+		}
+	}
+	tokens := auth.NewIssuer(
+		jwtSecret,
+		time.Duration(cfg.JWTAccessTTL)*time.Minute,
+		time.Duration(cfg.JWTRefreshTTL)*time.Hour,
+	)
+
+	// Rate limits are counted in Redis so they hold across replicas
+	policies := make(map[string]ratelimit.Policy)
+	for group, value := range map[string]string{
+		handler.RateLimitAuth:   cfg.RateLimitAuth,
+		handler.RateLimitSearch: cfg.RateLimitSearch,
+		handler.RateLimitRead:   cfg.RateLimitRead,
+		handler.RateLimitWrite:  cfg.RateLimitWrite,
+	} {
+		policy, err := ratelimit.ParsePolicy(value)
+		if err != nil {
+			return err
+		}
+		policies[group] = policy
+	}
+	limiter := ratelimit.NewLimiter(client, policies)
 
 	// Initialize handler layer
-	handler := handler.NewHandler(service, logger)
+	handler := handler.NewHandler(service, tokens, limiter, cfg.CacheControl, logger)
 
 	// Initialize Gin router
 	router := gin.Default()
@@ -73,12 +153,26 @@ func Run(logger *log.Logger) error {
 		}
 	}()
 
+	// Purge songs that have been in the trash longer than the retention period
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	if cfg.TrashRetention > 0 && cfg.TrashPurgeInterval > 0 {
+		go service.RunTrashPurger(
+			purgeCtx,
+			time.Duration(cfg.TrashRetention)*24*time.Hour,
+			time.Duration(cfg.TrashPurgeInterval)*time.Minute,
+		)
+	} else {
+		logger.Println("TRASH_RETENTION_DAYS is 0, deleted songs stay in the trash until purged by hand")
+	}
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit // Wait for termination signal
 	logger.Println("Shutting down server...")
+	stopPurge()
 
 	// Create a context with a timeout for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -92,3 +186,21 @@ func Run(logger *log.Logger) error {
 	logger.Println("Server shutdown gracefully")
 	return nil
 }
+
+// requireSecrets fails outside development when a secret that every replica
+// must share is not set. A random one would break cursors and sign-ins on
+// restart and across replicas.
+func requireSecrets(cfg *config.Config) error {
+	if cfg.AppEnv == config.Development {
+		return nil
+	}
+	for _, secret := range []struct{ name, value string }{
+		{"CURSOR_SECRET", cfg.CursorSecret},
+		{"JWT_SECRET", cfg.JWTSecret},
+	} {
+		if secret.value == "" {
+			return fmt.Errorf("%s is not set; set it, or set APP_ENV=%s to use a random secret", secret.name, config.Development)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ruziba3vich/music_lib/cmd/app"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/internal/service"
+	"github.com/ruziba3vich/music_lib/pkg/config"
+)
+
+const usage = `Usage: music_lib_cli <command> [flags]
+
+Commands:
+  import            import songs from a CSV, JSON or NDJSON file
+  export            export songs to a CSV, JSON or NDJSON file
+  detect-languages  detect the language of songs stored before detection existed
+  create-admin      create an admin account
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	logger := log.New(os.Stderr, "[MusicLib] ", log.Ldate|log.Ltime)
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:], logger)
+	case "export":
+		err = runExport(os.Args[2:], logger)
+	case "detect-languages":
+		err = runDetectLanguages(os.Args[2:], logger)
+	case "create-admin":
+		err = runCreateAdmin(os.Args[2:], logger)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		logger.Fatal(err)
+	}
+}
+
+// connect builds the service layer from the same configuration as the server.
+func connect(logger *log.Logger) (*service.Service, error) {
+	service, _, err := app.NewService(config.LoadConfig(), logger)
+	return service, err
+}
+
+// runImport imports songs from the file named by the first argument, or from
+// standard input when it is "-".
+func runImport(args []string, logger *log.Logger) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "csv, json or ndjson (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate the file without importing")
+	batchSize := flags.Int("batch-size", 500, "songs per transaction")
+	reportPath := flags.String("report", "", "write the rows that failed to this CSV file")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: music_lib_cli import [flags] <file|->")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = formatFromPath(path)
+	}
+	if !models.ValidBulkFormat(*format) {
+		return fmt.Errorf("unknown format %q, use -format csv, json or ndjson", *format)
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	service, err := connect(logger)
+	if err != nil {
+		return err
+	}
+	report, importErr := service.ImportSongs(context.Background(), input, models.ImportOptions{
+		Format:    *format,
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	})
+	if report == nil {
+		return importErr
+	}
+
+	if *reportPath != "" {
+		if err := writeImportReport(*reportPath, report); err != nil {
+			return err
+		}
+	} else {
+		for _, rowErr := range report.Errors {
+			fmt.Fprintf(os.Stderr, "row %d: %s\n", rowErr.Row, rowErr.Error)
+		}
+	}
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d of %d rows, %d failed\n", verb, report.Imported, report.Rows, report.Failed)
+	return importErr
+}
+
+// runExport writes songs to the file given with -o, or to standard output.
+func runExport(args []string, logger *log.Logger) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "-", "file to write, or - for standard output")
+	format := flags.String("format", "", "csv, json or ndjson (default: from the file extension, else ndjson)")
+	noLyrics := flags.Bool("no-lyrics", false, "leave out lyrics")
+	// Filter flags are read like the query parameters of GET /api/export,
+	// with dashes for underscores: -group-id, -released-from and so on.
+	filterValues := url.Values{}
+	allowed := make(map[string]bool, len(models.FilterParams))
+	for _, param := range models.FilterParams {
+		allowed[param] = true
+		flags.Func(strings.ReplaceAll(param, "_", "-"), exportFilterUsage[param], func(value string) error {
+			filterValues.Add(param, value)
+			return nil
+		})
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: music_lib_cli export [flags]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	filter, err := models.ParseSongFilter(filterValues, allowed)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = formatFromPath(*output)
+	}
+	if *format == "" {
+		*format = models.FormatNDJSON
+	}
+	if !models.ValidBulkFormat(*format) {
+		return fmt.Errorf("unknown format %q, use -format csv, json or ndjson", *format)
+	}
+
+	service, err := connect(logger)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if *output != "-" {
+		if file, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	count, err := service.ExportSongs(context.Background(), out, models.ExportOptions{
+		Format:     *format,
+		Filter:     filter,
+		OmitLyrics: *noLyrics,
+	})
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d songs\n", count)
+	return nil
+}
+
+// exportFilterUsage describes the filter flags of the export command.
+var exportFilterUsage = map[string]string{
+	"name":          "only songs whose name contains this",
+	"group":         "only songs whose group contains this",
+	"group_id":      "only songs of the group with this ID",
+	"artist":        "only songs by this artist",
+	"genre":         "only songs in this genre or its subgenres",
+	"tag":           "only songs with this tag; repeat to require several",
+	"language":      "only songs in this language (en, ru or uz)",
+	"released_from": "only songs released on or after this date (YYYY-MM-DD or RFC 3339)",
+	"released_to":   "only songs released on or before this date",
+	"created_from":  "only songs added on or after this date (YYYY-MM-DD or RFC 3339)",
+	"created_to":    "only songs added on or before this date",
+}
+
+// runDetectLanguages re-detects the language of every song and stores it where it changed.
+func runDetectLanguages(args []string, logger *log.Logger) error {
+	flags := flag.NewFlagSet("detect-languages", flag.ExitOnError)
+	batchSize := flags.Int("batch-size", 500, "songs per transaction")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: music_lib_cli detect-languages [flags]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 || *batchSize <= 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	service, err := connect(logger)
+	if err != nil {
+		return err
+	}
+	changed, err := service.DetectSongLanguages(context.Background(), *batchSize)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated the language of %d songs\n", changed)
+	return nil
+}
+
+// runCreateAdmin creates an admin account. The password is read from
+// ADMIN_PASSWORD, or from the first line of standard input, so it stays out of
+// the shell history.
+func runCreateAdmin(args []string, logger *log.Logger) error {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := flags.String("username", "", "name of the admin account")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: music_lib_cli create-admin -username <name> < password-file")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 || *username == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	password, ok := os.LookupEnv("ADMIN_PASSWORD")
+	if !ok || password == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	service, err := connect(logger)
+	if err != nil {
+		return err
+	}
+	user, err := service.CreateAdmin(context.Background(), models.Credentials{Username: *username, Password: password})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created admin %s (%s)\n", user.Username, user.ID)
+	return nil
+}
+
+// writeImportReport writes the failed rows as CSV with the columns row and error.
+func writeImportReport(path string, report *models.ImportReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	if err := w.Write([]string{"row", "error"}); err != nil {
+		return err
+	}
+	for _, rowErr := range report.Errors {
+		if err := w.Write([]string{strconv.Itoa(rowErr.Row), rowErr.Error}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// formatFromPath guesses the bulk format from a file extension.
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return models.FormatCSV
+	case ".json":
+		return models.FormatJSON
+	case ".ndjson", ".jsonl":
+		return models.FormatNDJSON
+	}
+	return ""
+}
@@ -0,0 +1,268 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// ArtistSeparator separates artist names within a CSV cell.
+const ArtistSeparator = ";"
+
+// Columns are the CSV columns, in export order. Imports match them by header
+// name, so they may come in any order and unknown columns are ignored.
+var Columns = []string{"id", "name", "group", "group_id", "artists", "release_date", "link", "lyrics"}
+
+// Record is one song as it appears in an import or export file.
+type Record struct {
+	ID          *uuid.UUID `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Group       string     `json:"group"`
+	GroupID     *uuid.UUID `json:"group_id,omitempty"`
+	Artists     []string   `json:"artists"`
+	ReleaseDate string     `json:"release_date"`
+	Link        string     `json:"link"`
+	Lyrics      *string    `json:"lyrics,omitempty"`
+}
+
+// RowError is a row that could not be decoded. Reading can continue after it.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader decodes records one at a time, so imports never hold the whole file.
+type Reader interface {
+	// Next returns the next record and its row number, a *RowError for a row
+	// that is malformed, or io.EOF at the end. Other errors are fatal.
+	Next() (Record, int, error)
+}
+
+// NewReader returns a reader for the given format. A leading UTF-8 byte
+// order mark, as spreadsheet programs write, is skipped.
+func NewReader(r io.Reader, format string) (Reader, error) {
+	r = skipBOM(r)
+	switch format {
+	case models.FormatCSV:
+		return newCSVReader(r)
+	case models.FormatJSON:
+		return newJSONReader(r)
+	case models.FormatNDJSON:
+		return &ndjsonReader{scanner: newLineScanner(r)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func skipBOM(r io.Reader) io.Reader {
+	buf := bufio.NewReader(r)
+	if bom, err := buf.Peek(len("\ufeff")); err == nil && string(bom) == "\ufeff" {
+		buf.Discard(len(bom))
+	}
+	return buf
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("CSV header has no name column")
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (Record, int, error) {
+	fields, err := r.reader.Read()
+	if err == io.EOF {
+		return Record{}, 0, io.EOF
+	}
+	r.row++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Record{}, r.row, &RowError{Row: r.row, Err: parseErr.Err}
+	}
+	if err != nil {
+		return Record{}, r.row, err
+	}
+
+	get := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+	record := Record{
+		Name:        get("name"),
+		Group:       get("group"),
+		ReleaseDate: get("release_date"),
+		Link:        get("link"),
+	}
+	if i, ok := r.columns["lyrics"]; ok && i < len(fields) {
+		lyrics := fields[i]
+		record.Lyrics = &lyrics
+	}
+	for _, artist := range strings.Split(get("artists"), ArtistSeparator) {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			record.Artists = append(record.Artists, artist)
+		}
+	}
+	for column, target := range map[string]**uuid.UUID{"id": &record.ID, "group_id": &record.GroupID} {
+		if value := get(column); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return Record{}, r.row, &RowError{Row: r.row, Err: fmt.Errorf("invalid %s %q", column, value)}
+			}
+			*target = &id
+		}
+	}
+	return record, r.row, nil
+}
+
+// jsonReader walks a JSON array element by element.
+type jsonReader struct {
+	decoder *json.Decoder
+	row     int
+	done    bool
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("JSON import must be an array of songs")
+	}
+	return &jsonReader{decoder: decoder}, nil
+}
+
+func (r *jsonReader) Next() (Record, int, error) {
+	if r.done || !r.decoder.More() {
+		r.done = true
+		return Record{}, 0, io.EOF
+	}
+	r.row++
+	var raw json.RawMessage
+	if err := r.decoder.Decode(&raw); err != nil {
+		return Record{}, r.row, fmt.Errorf("row %d: %w", r.row, err)
+	}
+	record, err := decodeRecord(raw)
+	if err != nil {
+		return Record{}, r.row, &RowError{Row: r.row, Err: err}
+	}
+	return record, r.row, nil
+}
+
+// ndjsonReader reads one JSON object per line, skipping blank lines.
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (r *ndjsonReader) Next() (Record, int, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		r.row++
+		record, err := decodeRecord(line)
+		if err != nil {
+			return Record{}, r.row, &RowError{Row: r.row, Err: err}
+		}
+		return record, r.row, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, r.row, err
+	}
+	return Record{}, 0, io.EOF
+}
+
+// maxLineSize bounds one NDJSON line, which has to fit a song with its lyrics.
+const maxLineSize = 4 << 20
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	return scanner
+}
+
+func decodeRecord(data []byte) (Record, error) {
+	var record Record
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&record); err != nil {
+		return Record{}, err
+	}
+	return record, nil
+}
+
+// Song turns a record into a song, validating it. Records without an ID get a new one.
+func (r Record) Song() (models.Song, error) {
+	song := models.Song{ID: uuid.New()}
+	if r.ID != nil {
+		song.ID = *r.ID
+	}
+	state := models.SongState{
+		Name:    strings.TrimSpace(r.Name),
+		Group:   strings.TrimSpace(r.Group),
+		GroupID: r.GroupID,
+		Artists: r.Artists,
+		Link:    strings.TrimSpace(r.Link),
+	}
+	if r.Lyrics != nil {
+		state.Lyrics = *r.Lyrics
+	}
+	if r.ReleaseDate != "" {
+		date, err := parseDate(r.ReleaseDate)
+		if err != nil {
+			return song, fmt.Errorf("%w: release_date must be YYYY-MM-DD or RFC 3339", models.ErrInvalidSong)
+		}
+		state.ReleaseDate = date
+	}
+	if err := state.Validate(); err != nil {
+		return song, err
+	}
+	song.ApplyState(state)
+	return song, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,207 @@
+package bulk
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// result is what a reader returned for one row.
+type result struct {
+	row    int
+	name   string
+	rowErr bool
+}
+
+// readAll drains a reader, failing the test on a fatal error.
+func readAll(t *testing.T, reader Reader) []result {
+	t.Helper()
+	var results []result
+	for {
+		record, row, err := reader.Next()
+		if err == io.EOF {
+			return results
+		}
+		var rowErr *RowError
+		switch {
+		case errors.As(err, &rowErr):
+			if rowErr.Row != row {
+				t.Errorf("RowError.Row = %d, Next returned row %d", rowErr.Row, row)
+			}
+			results = append(results, result{row: row, rowErr: true})
+		case err != nil:
+			t.Fatalf("row %d: fatal error %v", row, err)
+		default:
+			results = append(results, result{row: row, name: record.Name})
+		}
+	}
+}
+
+func newTestReader(t *testing.T, format, input string) Reader {
+	t.Helper()
+	reader, err := NewReader(strings.NewReader(input), format)
+	if err != nil {
+		t.Fatalf("NewReader(%s): %v", format, err)
+	}
+	return reader
+}
+
+func TestCSVReaderMapsHeader(t *testing.T) {
+	groupID := uuid.MustParse("7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23")
+	input := "\ufeffLyrics, Name ,colour,artists,group_id,release_date\n" +
+		"\"line one\nline two\",Song,red, One ; Two ;,7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23,2020-05-01\n" +
+		"\"\",Short\n"
+	reader := newTestReader(t, models.FormatCSV, input)
+
+	record, row, err := reader.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row != 1 {
+		t.Errorf("row = %d, want 1, not counting the header", row)
+	}
+	want := Record{
+		Name:        "Song",
+		GroupID:     &groupID,
+		Artists:     []string{"One", "Two"},
+		ReleaseDate: "2020-05-01",
+		Lyrics:      ptr("line one\nline two"),
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("record = %+v, want %+v", record, want)
+	}
+
+	// Missing trailing cells are empty; an empty lyrics cell is still given.
+	record, row, err = reader.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row != 2 || record.Name != "Short" || record.Lyrics == nil || *record.Lyrics != "" || record.Artists != nil {
+		t.Errorf("row %d = %+v, want the short row", row, record)
+	}
+	if _, _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Next at the end = %v, want io.EOF", err)
+	}
+}
+
+func TestCSVReaderWithoutLyricsColumn(t *testing.T) {
+	record, _, err := newTestReader(t, models.FormatCSV, "name\nSong\n").Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Lyrics != nil {
+		t.Errorf("Lyrics = %q, want nil so existing lyrics are not cleared", *record.Lyrics)
+	}
+}
+
+func TestCSVReaderHeaderErrors(t *testing.T) {
+	for _, input := range []string{"", "title,group\nSong,Band\n"} {
+		if _, err := NewReader(strings.NewReader(input), models.FormatCSV); err == nil {
+			t.Errorf("NewReader(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestReadersContinueAfterMalformedRows(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+		want   []result
+	}{
+		{
+			format: models.FormatCSV,
+			input:  "name,id\nOne,\nTw\"o,\nThree,not-a-uuid\nFour,\n",
+			want:   []result{{row: 1, name: "One"}, {row: 2, rowErr: true}, {row: 3, rowErr: true}, {row: 4, name: "Four"}},
+		},
+		{
+			format: models.FormatJSON,
+			input:  `[{"name": "One"}, {"name": 2}, {"name": "Three", "colour": "red"}, {"name": "Four"}]`,
+			want:   []result{{row: 1, name: "One"}, {row: 2, rowErr: true}, {row: 3, rowErr: true}, {row: 4, name: "Four"}},
+		},
+		{
+			format: models.FormatNDJSON,
+			input:  "{\"name\": \"One\"}\n\n{\"name\": \n{\"name\": \"Three\", \"colour\": \"red\"}\r\n{\"name\": \"Four\"}",
+			want:   []result{{row: 1, name: "One"}, {row: 2, rowErr: true}, {row: 3, rowErr: true}, {row: 4, name: "Four"}},
+		},
+	}
+	for _, tt := range tests {
+		if got := readAll(t, newTestReader(t, tt.format, tt.input)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: read %+v, want %+v", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestReadersSkipBOM(t *testing.T) {
+	for format, input := range map[string]string{
+		models.FormatCSV:    "\ufeffname\nSong\n",
+		models.FormatJSON:   "\ufeff[{\"name\": \"Song\"}]",
+		models.FormatNDJSON: "\ufeff{\"name\": \"Song\"}\n",
+	} {
+		want := []result{{row: 1, name: "Song"}}
+		if got := readAll(t, newTestReader(t, format, input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: read %+v, want %+v", format, got, want)
+		}
+	}
+}
+
+func TestJSONArrayAndNDJSON(t *testing.T) {
+	// A JSON import is one array; NDJSON is one object per line.
+	if _, err := NewReader(strings.NewReader(`{"name": "Song"}`), models.FormatJSON); err == nil {
+		t.Error("JSON import of an object succeeded, want an array required")
+	}
+	want := []result{{row: 1, name: "One"}, {row: 2, name: "Two"}}
+	if got := readAll(t, newTestReader(t, models.FormatJSON, "[\n{\"name\": \"One\"},\n{\"name\": \"Two\"}\n]")); !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON: read %+v, want %+v", got, want)
+	}
+	if got := readAll(t, newTestReader(t, models.FormatNDJSON, "{\"name\": \"One\"}\n{\"name\": \"Two\"}\n")); !reflect.DeepEqual(got, want) {
+		t.Errorf("NDJSON: read %+v, want %+v", got, want)
+	}
+
+	// Broken JSON syntax loses the rest of the array, so it stops the import.
+	reader := newTestReader(t, models.FormatJSON, `[{"name": "One"}, {"name": ]`)
+	if _, _, err := reader.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := reader.Next()
+	var rowErr *RowError
+	if err == nil || err == io.EOF || errors.As(err, &rowErr) {
+		t.Errorf("Next after a syntax error = %v, want a fatal error", err)
+	}
+
+	if _, err := NewReader(strings.NewReader(""), "xml"); err == nil {
+		t.Error("NewReader(xml) succeeded, want an error")
+	}
+}
+
+func TestRecordSong(t *testing.T) {
+	id := uuid.New()
+	song, err := Record{ID: &id, Name: " Song ", Artists: []string{"One"}, ReleaseDate: "2020-05-01", Lyrics: ptr("la")}.Song()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if song.ID != id || song.Name != "Song" || song.Lyrics != "la" || song.ReleaseDate.Format("2006-01-02") != "2020-05-01" {
+		t.Errorf("Song = %+v", song)
+	}
+	if song, err := (Record{Name: "Song"}).Song(); err != nil || song.ID == uuid.Nil {
+		t.Errorf("Song without an ID = %+v, %v, want a new ID", song, err)
+	}
+
+	for _, record := range []Record{
+		{Name: " "},
+		{Name: "Song", ReleaseDate: "May 2020"},
+		{Name: "Song", Link: "ftp://example.com"},
+	} {
+		if _, err := record.Song(); !errors.Is(err, models.ErrInvalidSong) {
+			t.Errorf("Song(%+v) error = %v, want ErrInvalidSong", record, err)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
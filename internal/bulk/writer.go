@@ -0,0 +1,162 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// Writer encodes records one at a time. Output is buffered; Close writes out
+// whatever is left and finishes the document.
+type Writer interface {
+	Write(Record) error
+	Close() error
+}
+
+// NewWriter returns a writer for the given format. Without lyrics, the lyrics
+// column or field is left out.
+func NewWriter(w io.Writer, format string, lyrics bool) (Writer, error) {
+	buf := bufio.NewWriter(w)
+	switch format {
+	case models.FormatCSV:
+		return &csvWriter{w: csv.NewWriter(buf), lyrics: lyrics}, nil
+	case models.FormatJSON:
+		return &jsonWriter{buf: buf}, nil
+	case models.FormatNDJSON:
+		return &ndjsonWriter{buf: buf, encoder: json.NewEncoder(buf)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// NewRecord turns a song into a record, leaving out its lyrics unless asked for.
+func NewRecord(song *models.Song, lyrics bool) Record {
+	record := Record{
+		ID:      &song.ID,
+		Name:    song.Name,
+		Group:   song.Group,
+		GroupID: song.GroupID,
+		Artists: song.Artists,
+		Link:    song.Link,
+	}
+	if record.Artists == nil {
+		record.Artists = []string{}
+	}
+	if !song.ReleaseDate.IsZero() {
+		record.ReleaseDate = song.ReleaseDate.Format(time.DateOnly)
+	}
+	if lyrics {
+		record.Lyrics = &song.Lyrics
+	}
+	return record
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	lyrics  bool
+	started bool
+}
+
+// columns is the header row; lyrics is the last of Columns.
+func (w *csvWriter) columns() []string {
+	if w.lyrics {
+		return Columns
+	}
+	return Columns[:len(Columns)-1]
+}
+
+func (w *csvWriter) Write(record Record) error {
+	if !w.started {
+		w.started = true
+		if err := w.w.Write(w.columns()); err != nil {
+			return err
+		}
+	}
+	row := []string{
+		record.ID.String(),
+		record.Name,
+		record.Group,
+		"",
+		strings.Join(record.Artists, ArtistSeparator),
+		record.ReleaseDate,
+		record.Link,
+	}
+	if record.GroupID != nil {
+		row[3] = record.GroupID.String()
+	}
+	if w.lyrics {
+		row = append(row, ptrString(record.Lyrics))
+	}
+	return w.w.Write(row)
+}
+
+func (w *csvWriter) Close() error {
+	if !w.started {
+		w.started = true
+		if err := w.w.Write(w.columns()); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// jsonWriter writes a JSON array with one record per line.
+type jsonWriter struct {
+	buf   *bufio.Writer
+	count int
+}
+
+func (w *jsonWriter) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if w.count == 0 {
+		separator = "[\n"
+	}
+	w.count++
+	if _, err := w.buf.WriteString(separator); err != nil {
+		return err
+	}
+	_, err = w.buf.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := w.buf.WriteString(end); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+type ndjsonWriter struct {
+	buf     *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record Record) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.buf.Flush()
+}
+
+func ptrString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
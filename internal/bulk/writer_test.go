@@ -0,0 +1,103 @@
+package bulk
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	groupID := uuid.New()
+	songs := []models.Song{
+		{
+			ID:          uuid.New(),
+			Name:        `Song, "quoted"`,
+			Group:       "Band",
+			GroupID:     &groupID,
+			Artists:     []string{"One", "Two"},
+			Lyrics:      "Verse 1:\nfirst, line\n\n[Chorus]\n\"la\"",
+			Link:        "https://example.com/song",
+			ReleaseDate: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{ID: uuid.New(), Name: "Bare"},
+	}
+
+	for _, format := range []string{models.FormatCSV, models.FormatJSON, models.FormatNDJSON} {
+		for _, lyrics := range []bool{true, false} {
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, format, lyrics)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range songs {
+				if err := writer.Write(NewRecord(&songs[i], lyrics)); err != nil {
+					t.Fatalf("%s: Write: %v", format, err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("%s: Close: %v", format, err)
+			}
+
+			reader, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatalf("%s: NewReader: %v\n%s", format, err, buf.String())
+			}
+			for i, want := range songs {
+				record, row, err := reader.Next()
+				if err != nil {
+					t.Fatalf("%s (lyrics %t): row %d: %v", format, lyrics, i+1, err)
+				}
+				if row != i+1 {
+					t.Errorf("%s (lyrics %t): row = %d, want %d", format, lyrics, row, i+1)
+				}
+				if !lyrics && record.Lyrics != nil {
+					t.Errorf("%s: lyrics exported with lyrics off: %q", format, *record.Lyrics)
+				}
+				got, err := record.Song()
+				if err != nil {
+					t.Fatalf("%s (lyrics %t): row %d: %v", format, lyrics, row, err)
+				}
+				if !lyrics {
+					want.Lyrics = ""
+				}
+				if want.Artists == nil {
+					want.Artists = []string{}
+				}
+				if got.Artists == nil {
+					got.Artists = []string{}
+				}
+				if got.ID != want.ID || !reflect.DeepEqual(got.State(), want.State()) {
+					t.Errorf("%s (lyrics %t): row %d =\n%+v\nwant\n%+v", format, lyrics, row, got.State(), want.State())
+				}
+			}
+			if _, _, err := reader.Next(); err != io.EOF {
+				t.Errorf("%s (lyrics %t): Next after the last song = %v, want io.EOF", format, lyrics, err)
+			}
+		}
+	}
+}
+
+func TestEmptyExport(t *testing.T) {
+	// An empty JSON export is still an array; an empty CSV export has no rows to import.
+	for format, want := range map[string]string{models.FormatJSON: "[]\n", models.FormatNDJSON: ""} {
+		var buf bytes.Buffer
+		writer, err := NewWriter(&buf, format, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: empty export = %q, want %q", format, buf.String(), want)
+		}
+	}
+	if _, err := NewWriter(io.Discard, "xml", true); err == nil {
+		t.Error("NewWriter(xml) succeeded, want an error")
+	}
+}
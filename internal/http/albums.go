@@ -0,0 +1,411 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Create an album
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param album body models.Album true "Album object"
+// @Success 201 {object} models.Album
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "group not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/albums [post]
+func (h *Handler) CreateAlbumHandler(c *gin.Context) {
+	var album models.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	album.ID = uuid.New()
+
+	if err := h.repo.CreateAlbum(c, &album); err != nil {
+		h.logger.Printf("ERROR: Failed to create album: %v", err)
+		h.respondAlbumError(c, err, "failed to create album")
+		return
+	}
+
+	c.JSON(http.StatusCreated, album)
+}
+
+// @Summary Get all albums
+// @Description Fetches albums ordered by title
+// @Tags albums
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.Album
+// @Failure 500 {object} map[string]string "failed to fetch albums"
+// @Router /api/albums [get]
+func (h *Handler) GetAlbumsHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	albums, err := h.repo.GetAlbums(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch albums: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch albums"})
+		return
+	}
+
+	c.JSON(http.StatusOK, albums)
+}
+
+// @Summary Get an album by ID
+// @Description Fetches an album with its releases
+// @Tags albums
+// @Produce json
+// @Param id path string true "Album ID"
+// @Success 200 {object} models.Album
+// @Failure 404 {object} map[string]string
+// @Router /api/albums/{id} [get]
+func (h *Handler) GetAlbumByIDHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	album, err := h.repo.GetAlbumByID(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch album ID %s: %v", id, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "album not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// @Summary Update an album
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path string true "Album ID"
+// @Param album body models.Album true "Album data"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "album not found"
+// @Failure 500 {object} map[string]string "failed to update album"
+// @Router /api/albums/{id} [put]
+func (h *Handler) UpdateAlbumHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid album ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid album ID"})
+		return
+	}
+
+	var album models.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	album.ID = id
+
+	if err := h.repo.UpdateAlbum(c, &album); err != nil {
+		h.logger.Printf("ERROR: Failed to update album ID %s: %v", id, err)
+		h.respondAlbumError(c, err, "failed to update album")
+		return
+	}
+
+	updated, err := h.repo.GetAlbumByID(c, id.String())
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch album ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update album"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Delete an album
+// @Description Deletes an album. Its releases are kept without an album
+// @Tags albums
+// @Param id path string true "Album ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "album not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/albums/{id} [delete]
+func (h *Handler) DeleteAlbumHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeleteAlbum(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete album ID %s: %v", id, err)
+		h.respondAlbumError(c, err, "failed to delete album")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "album deleted"})
+}
+
+// @Summary Create a release
+// @Description Adds a release, optionally as an edition of an album. Build its tracklist with the tracks endpoints
+// @Tags releases
+// @Accept json
+// @Produce json
+// @Param release body models.Release true "Release object"
+// @Success 201 {object} models.Release
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "album not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/releases [post]
+func (h *Handler) CreateReleaseHandler(c *gin.Context) {
+	var release models.Release
+	if err := c.ShouldBindJSON(&release); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	release.ID = uuid.New()
+
+	if err := h.repo.CreateRelease(c, &release); err != nil {
+		h.logger.Printf("ERROR: Failed to create release: %v", err)
+		h.respondAlbumError(c, err, "failed to create release")
+		return
+	}
+
+	c.JSON(http.StatusCreated, release)
+}
+
+// @Summary Get all releases
+// @Description Fetches releases, newest first
+// @Tags releases
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.Release
+// @Failure 500 {object} map[string]string "failed to fetch releases"
+// @Router /api/releases [get]
+func (h *Handler) GetReleasesHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	releases, err := h.repo.GetReleases(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch releases: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch releases"})
+		return
+	}
+
+	c.JSON(http.StatusOK, releases)
+}
+
+// @Summary Get a release by ID
+// @Description Fetches a release with its tracklist in playing order
+// @Tags releases
+// @Produce json
+// @Param id path string true "Release ID"
+// @Success 200 {object} models.Release
+// @Failure 404 {object} map[string]string
+// @Router /api/releases/{id} [get]
+func (h *Handler) GetReleaseByIDHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	release, err := h.repo.GetReleaseByID(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch release ID %s: %v", id, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "release not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+// @Summary Update a release
+// @Description Updates the release details. The tracklist is left untouched
+// @Tags releases
+// @Accept json
+// @Produce json
+// @Param id path string true "Release ID"
+// @Param release body models.Release true "Release data"
+// @Success 200 {object} models.Release
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "release not found"
+// @Failure 500 {object} map[string]string "failed to update release"
+// @Router /api/releases/{id} [put]
+func (h *Handler) UpdateReleaseHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid release ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid release ID"})
+		return
+	}
+
+	var release models.Release
+	if err := c.ShouldBindJSON(&release); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	release.ID = id
+
+	if err := h.repo.UpdateRelease(c, &release); err != nil {
+		h.logger.Printf("ERROR: Failed to update release ID %s: %v", id, err)
+		h.respondAlbumError(c, err, "failed to update release")
+		return
+	}
+
+	updated, err := h.repo.GetReleaseByID(c, id.String())
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch release ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update release"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Delete a release
+// @Description Deletes a release and its tracklist. The songs are kept
+// @Tags releases
+// @Param id path string true "Release ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "release not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/releases/{id} [delete]
+func (h *Handler) DeleteReleaseHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeleteRelease(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete release ID %s: %v", id, err)
+		h.respondAlbumError(c, err, "failed to delete release")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "release deleted"})
+}
+
+// @Summary Set a release's tracklist
+// @Description Replaces the whole tracklist. Tracks are played in the order given within each disc (disc_number defaults to 1); track numbers are assigned from 1
+// @Tags releases
+// @Accept json
+// @Produce json
+// @Param id path string true "Release ID"
+// @Param tracks body []models.TrackInput true "Tracks in playing order"
+// @Success 200 {object} models.Release
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "release or song not found"
+// @Failure 500 {object} map[string]string "failed to set tracklist"
+// @Router /api/releases/{id}/tracks [put]
+func (h *Handler) SetReleaseTracksHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var tracks []models.TrackInput
+	if err := c.ShouldBindJSON(&tracks); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	release, err := h.repo.SetReleaseTracks(c, id, tracks)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set tracklist of release ID %s: %v", id, err)
+		h.respondAlbumError(c, err, "failed to set tracklist")
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+// @Summary Add a track to a release
+// @Description Inserts a song at track_number on disc_number, shifting the following tracks down. A missing track_number appends to the disc
+// @Tags releases
+// @Accept json
+// @Produce json
+// @Param id path string true "Release ID"
+// @Param track body models.TrackInput true "Track"
+// @Success 200 {object} models.Release
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "release or song not found"
+// @Failure 500 {object} map[string]string "failed to add track"
+// @Router /api/releases/{id}/tracks [post]
+func (h *Handler) AddReleaseTrackHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var track models.TrackInput
+	if err := c.ShouldBindJSON(&track); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	release, err := h.repo.AddReleaseTrack(c, id, track)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to add track to release ID %s: %v", id, err)
+		h.respondAlbumError(c, err, "failed to add track")
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+// @Summary Move a track within a release
+// @Description Moves a track to track_number on disc_number and renumbers the rest of the tracklist
+// @Tags releases
+// @Accept json
+// @Produce json
+// @Param id path string true "Release ID"
+// @Param track_id path string true "Track ID"
+// @Param position body models.TrackPosition true "New position"
+// @Success 200 {object} models.Release
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "release or track not found"
+// @Failure 500 {object} map[string]string "failed to move track"
+// @Router /api/releases/{id}/tracks/{track_id}/move [post]
+func (h *Handler) MoveReleaseTrackHandler(c *gin.Context) {
+	id, trackID := c.Param("id"), c.Param("track_id")
+
+	var position models.TrackPosition
+	if err := c.ShouldBindJSON(&position); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	release, err := h.repo.MoveReleaseTrack(c, id, trackID, position)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to move track ID %s of release ID %s: %v", trackID, id, err)
+		h.respondAlbumError(c, err, "failed to move track")
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+// @Summary Remove a track from a release
+// @Description Removes a track and renumbers the following tracks
+// @Tags releases
+// @Produce json
+// @Param id path string true "Release ID"
+// @Param track_id path string true "Track ID"
+// @Success 200 {object} models.Release
+// @Failure 404 {object} map[string]string "release or track not found"
+// @Failure 500 {object} map[string]string "failed to remove track"
+// @Router /api/releases/{id}/tracks/{track_id} [delete]
+func (h *Handler) RemoveReleaseTrackHandler(c *gin.Context) {
+	id, trackID := c.Param("id"), c.Param("track_id")
+
+	release, err := h.repo.RemoveReleaseTrack(c, id, trackID)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to remove track ID %s from release ID %s: %v", trackID, id, err)
+		h.respondAlbumError(c, err, "failed to remove track")
+		return
+	}
+
+	c.JSON(http.StatusOK, release)
+}
+
+// respondAlbumError maps album and release errors to a status code
+func (h *Handler) respondAlbumError(c *gin.Context, err error, message string) {
+	switch {
+	case isNotFound(err), isForeignKeyViolation(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
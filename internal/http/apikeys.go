@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Create an API key
+// @Description Creates a key for a machine client. The key is only returned in this response; the server keeps a hash of it. Admins only
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body models.APIKeyInput true "Name, scopes (songs:read, songs:write, admin) and optional expiry"
+// @Success 201 {object} models.NewAPIKey
+// @Failure 400 {object} map[string]string "invalid request body or scope"
+// @Failure 401 {object} map[string]string "authentication required"
+// @Failure 403 {object} map[string]string "requires the admin role"
+// @Failure 500 {object} map[string]string
+// @Router /api/api-keys [post]
+func (h *Handler) CreateAPIKeyHandler(c *gin.Context) {
+	var input models.APIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	for _, scope := range input.Scopes {
+		if !models.ValidAPIKeyScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown scope " + scope})
+			return
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	key, err := h.repo.CreateAPIKey(c, input, currentUserID(c))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to create API key: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// @Summary Get all API keys
+// @Description Fetches API keys, newest first, including expired and revoked ones. Admins only
+// @Tags api-keys
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} map[string]string "authentication required"
+// @Failure 403 {object} map[string]string "requires the admin role"
+// @Failure 500 {object} map[string]string "failed to fetch API keys"
+// @Router /api/api-keys [get]
+func (h *Handler) GetAPIKeysHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	keys, err := h.repo.GetAPIKeys(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch API keys: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Revoke an API key
+// @Description Revokes a key immediately. The key stays listed with its revoked_at time. Admins only
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 401 {object} map[string]string "authentication required"
+// @Failure 403 {object} map[string]string "requires the admin role"
+// @Failure 404 {object} map[string]string "API key not found"
+// @Failure 500 {object} map[string]string "failed to revoke API key"
+// @Router /api/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKeyHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	key, err := h.repo.RevokeAPIKey(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to revoke API key ID %s: %v", id, err)
+		if isNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke API key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
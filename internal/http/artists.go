@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Create an artist
+// @Description Adds a new artist. Names are unique regardless of case and whitespace
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param artist body models.Artist true "Artist object"
+// @Success 201 {object} models.Artist
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string "artist already exists"
+// @Failure 500 {object} map[string]string
+// @Router /api/artists [post]
+func (h *Handler) CreateArtistHandler(c *gin.Context) {
+	var artist models.Artist
+	if err := c.ShouldBindJSON(&artist); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	artist.ID = uuid.New()
+
+	if err := h.repo.CreateArtist(c, &artist); err != nil {
+		h.logger.Printf("ERROR: Failed to create artist: %v", err)
+		h.respondArtistError(c, err, "failed to create artist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, artist)
+}
+
+// @Summary Get all artists
+// @Description Fetches artists ordered by name
+// @Tags artists
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.Artist
+// @Failure 500 {object} map[string]string "failed to fetch artists"
+// @Router /api/artists [get]
+func (h *Handler) GetArtistsHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	artists, err := h.repo.GetArtists(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch artists: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, artists)
+}
+
+// @Summary Get an artist by ID
+// @Tags artists
+// @Produce json
+// @Param id path string true "Artist ID"
+// @Success 200 {object} models.Artist
+// @Failure 404 {object} map[string]string
+// @Router /api/artists/{id} [get]
+func (h *Handler) GetArtistByIDHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	artist, err := h.repo.GetArtistByID(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch artist ID %s: %v", id, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "artist not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// @Summary Get songs by artist ID
+// @Description Fetches the songs an artist is credited on, in any role. tag_facets counts the tags across all of them
+// @Tags artists
+// @Produce json
+// @Param id path string true "Artist ID"
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param sort query string false "Sort key: created_at, release_date or name, prefixed with - for descending" default(created_at)
+// @Param cursor query string false "next_cursor or prev_cursor from a previous page"
+// @Success 200 {object} models.SongPage
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid cursor"
+// @Failure 500 {object} map[string]string "failed to fetch songs"
+// @Router /api/artists/{id}/songs [get]
+func (h *Handler) GetSongsByArtistIDHandler(c *gin.Context) {
+	id := c.Param("id")
+	// Artist names are unique once normalized, so the artist's songs are the ones filtered by its name.
+	if artist, err := h.repo.GetArtistByID(c, id); err == nil && h.listingNotModified(c, models.SongFilter{Artist: artist.Name}) {
+		return
+	}
+
+	songs, err := h.repo.GetSongsByArtistID(c, id, getPageRequest(c))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch songs for artist ID %s: %v", id, err)
+		h.respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, songs)
+}
+
+// @Summary Update an artist
+// @Description Updates an artist. Renaming also renames the artist in the artists list of its songs
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path string true "Artist ID"
+// @Param artist body models.Artist true "Artist data"
+// @Success 200 {object} models.Artist
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "artist not found"
+// @Failure 409 {object} map[string]string "artist already exists"
+// @Failure 500 {object} map[string]string "failed to update artist"
+// @Router /api/artists/{id} [put]
+func (h *Handler) UpdateArtistHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid artist ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid artist ID"})
+		return
+	}
+
+	var artist models.Artist
+	if err := c.ShouldBindJSON(&artist); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	artist.ID = id
+
+	if err := h.repo.UpdateArtist(c, &artist); err != nil {
+		h.logger.Printf("ERROR: Failed to update artist ID %s: %v", id, err)
+		h.respondArtistError(c, err, "failed to update artist")
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// @Summary Delete an artist
+// @Description Deletes an artist that is not credited on any song
+// @Tags artists
+// @Param id path string true "Artist ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "artist not found"
+// @Failure 409 {object} map[string]string "artist is credited on songs"
+// @Failure 500 {object} map[string]string
+// @Router /api/artists/{id} [delete]
+func (h *Handler) DeleteArtistHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeleteArtist(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete artist ID %s: %v", id, err)
+		h.respondArtistError(c, err, "failed to delete artist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "artist deleted"})
+}
+
+// @Summary Get a song's artists
+// @Description Fetches the artists credited on a song, in order, with their roles
+// @Tags songs
+// @Produce json
+// @Param id path string true "Song ID"
+// @Success 200 {array} models.SongArtist
+// @Failure 500 {object} map[string]string "failed to fetch artists"
+// @Router /api/songs/{id}/artists [get]
+func (h *Handler) GetSongArtistsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	credits, err := h.repo.GetSongArtists(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch artists of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch artists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, credits)
+}
+
+// @Summary Set a song's artists
+// @Description Replaces the song's credits with the given artists, in order. Role defaults to performer
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param credits body []models.SongArtistInput true "Ordered credits"
+// @Success 200 {array} models.SongArtist
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "song or artist not found"
+// @Failure 500 {object} map[string]string "failed to set artists"
+// @Router /api/songs/{id}/artists [put]
+func (h *Handler) SetSongArtistsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var inputs []models.SongArtistInput
+	if err := c.ShouldBindJSON(&inputs); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	seen := make(map[uuid.UUID]bool, len(inputs))
+	for i := range inputs {
+		if inputs[i].Role == "" {
+			inputs[i].Role = models.RolePerformer
+		}
+		if !models.ValidArtistRole(inputs[i].Role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown role " + inputs[i].Role})
+			return
+		}
+		if seen[inputs[i].ArtistID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "artist " + inputs[i].ArtistID.String() + " is listed twice"})
+			return
+		}
+		seen[inputs[i].ArtistID] = true
+	}
+
+	credits, err := h.repo.SetSongArtists(c, id, inputs)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set artists of song ID %s: %v", id, err)
+		h.respondArtistError(c, err, "failed to set artists")
+		return
+	}
+
+	c.JSON(http.StatusOK, credits)
+}
+
+// respondArtistError maps artist errors to a status code
+func (h *Handler) respondArtistError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrDuplicateArtist), errors.Is(err, models.ErrArtistInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
+)
+
+// claimsKey is the gin context key holding the caller's *auth.Claims.
+const claimsKey = "claims"
+
+// authenticate reads the bearer token or API key, when there is one, and
+// stores the caller's claims on the context before any handler runs.
+// Requests without credentials continue anonymously; credentials that do not
+// verify are rejected rather than ignored, and count against the client IP's
+// auth rate limit, which is checked before any credentials are.
+func (h *Handler) authenticate(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		c.Next()
+		return
+	}
+	if h.throttleFailedAuth(c) {
+		return
+	}
+
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		claims, err := h.tokens.Parse(token, auth.KindAccess)
+		if err != nil {
+			h.logger.Printf("ERROR: Rejected access token: %v", err)
+			h.countFailedAuth(c)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+		c.Set(claimsKey, claims)
+		c.Next()
+		return
+	}
+
+	if secret, ok := strings.CutPrefix(header, "ApiKey "); ok {
+		key, err := h.repo.AuthenticateAPIKey(c, secret)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidAPIKey) {
+				h.countFailedAuth(c)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check API key"})
+			return
+		}
+		c.Set(claimsKey, auth.APIKeyClaims(key.ID, key.Role()))
+		c.Next()
+		return
+	}
+
+	h.countFailedAuth(c)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unsupported authorization scheme"})
+}
+
+// requireRole rejects anonymous callers with 401 and callers below role with 403.
+func (h *Handler) requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(c, role) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// hasRole reports whether the caller has at least role. When not, it aborts
+// the request the way requireRole does, for handlers where only some uses of
+// a route need the role.
+func hasRole(c *gin.Context, role string) bool {
+	claims, ok := currentClaims(c)
+	if !ok {
+		c.Header("WWW-Authenticate", "Bearer")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return false
+	}
+	if !models.RoleAtLeast(claims.Role, role) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "requires the " + role + " role"})
+		return false
+	}
+	return true
+}
+
+// requireUser rejects callers that are not signed in as a user. API keys act
+// for no particular user, so they cannot use per-user resources like playlists.
+func (h *Handler) requireUser(c *gin.Context) {
+	claims, ok := currentClaims(c)
+	if !ok {
+		c.Header("WWW-Authenticate", "Bearer")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+	if claims.Kind != auth.KindAccess {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "requires a user login"})
+		return
+	}
+	c.Next()
+}
+
+// currentClaims returns the claims of the signed-in caller, if any.
+func currentClaims(c *gin.Context) (*auth.Claims, bool) {
+	value, ok := c.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
+}
+
+// currentUserID returns the signed-in user's ID, or nil for anonymous callers and API keys.
+func currentUserID(c *gin.Context) *uuid.UUID {
+	claims, ok := currentClaims(c)
+	if !ok || claims.Kind != auth.KindAccess {
+		return nil
+	}
+	id, err := claims.UserID()
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// @Summary Register a user
+// @Description Creates a viewer account with a bcrypt-hashed password. Usernames are case-insensitive and must be 3 to 64 characters after collapsing whitespace
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.Credentials true "Username and password (at least 8 characters)"
+// @Success 201 {object} models.User
+// @Failure 400 {object} map[string]string "invalid request body or username"
+// @Failure 409 {object} map[string]string "username is already taken"
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/register [post]
+func (h *Handler) RegisterHandler(c *gin.Context) {
+	var credentials models.Credentials
+	if err := c.ShouldBindJSON(&credentials); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	user, err := h.repo.Register(c, credentials)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to register user: %v", err)
+		h.respondUserError(c, err, "failed to register user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// @Summary Log in
+// @Description Exchanges a username and password for an access token and a refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.Credentials true "Username and password"
+// @Success 200 {object} auth.TokenPair
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 401 {object} map[string]string "invalid username or password"
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/login [post]
+func (h *Handler) LoginHandler(c *gin.Context) {
+	var credentials models.Credentials
+	if err := c.ShouldBindJSON(&credentials); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	user, err := h.repo.Authenticate(c, credentials)
+	if err != nil {
+		h.respondUserError(c, err, "failed to log in")
+		return
+	}
+
+	h.issueTokens(c, user)
+}
+
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new token pair. The role is read again, so role changes take effect here
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} auth.TokenPair
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 401 {object} map[string]string "invalid or expired refresh token"
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/refresh [post]
+func (h *Handler) RefreshHandler(c *gin.Context) {
+	var request models.RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	claims, err := h.tokens.Parse(request.RefreshToken, auth.KindRefresh)
+	if err != nil {
+		h.logger.Printf("ERROR: Rejected refresh token: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+		return
+	}
+
+	user, err := h.repo.GetUserByID(c, claims.Subject)
+	if err != nil {
+		if isNotFound(err) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh tokens"})
+		return
+	}
+
+	h.issueTokens(c, user)
+}
+
+// @Summary Get the signed-in user
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.User
+// @Failure 401 {object} map[string]string "authentication required"
+// @Router /api/auth/me [get]
+func (h *Handler) MeHandler(c *gin.Context) {
+	claims, _ := currentClaims(c)
+
+	user, err := h.repo.GetUserByID(c, claims.Subject)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch user ID %s: %v", claims.Subject, err)
+		h.respondUserError(c, err, "failed to fetch user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) issueTokens(c *gin.Context, user *models.User) {
+	tokens, err := h.tokens.Issue(user.ID, user.Role)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to issue tokens for user ID %s: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// respondUserError maps account errors to a status code
+func (h *Handler) respondUserError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrBadCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrDuplicateUser):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidUsername), errors.Is(err, models.ErrInvalidPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
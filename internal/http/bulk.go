@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// bulkContentTypes maps request content types to bulk formats.
+var bulkContentTypes = map[string]string{
+	"text/csv":             models.FormatCSV,
+	"application/json":     models.FormatJSON,
+	"application/x-ndjson": models.FormatNDJSON,
+	"application/ndjson":   models.FormatNDJSON,
+}
+
+// @Summary Import songs in bulk
+// @Description Streams songs from a CSV file (with a header row; artists separated by ";"), a JSON array or NDJSON. Rows are validated one by one and valid ones are inserted in batches, one transaction per batch. The report lists every row that was skipped and why. With dry_run=true nothing is written
+// @Tags songs
+// @Accept plain
+// @Produce json
+// @Param format query string false "csv, json or ndjson; defaults to the format named by Content-Type"
+// @Param dry_run query bool false "Validate only" default(false)
+// @Param batch_size query int false "Songs per transaction" default(500)
+// @Param file body string true "Songs to import"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} map[string]string "unknown format or unreadable file"
+// @Failure 500 {object} map[string]string "failed to import songs"
+// @Router /api/songs/import [post]
+func (h *Handler) ImportSongsHandler(c *gin.Context) {
+	options := models.ImportOptions{
+		Format:    c.Query("format"),
+		BatchSize: getIntQueryParam(c, "batch_size", 500),
+	}
+	if options.Format == "" {
+		options.Format = bulkContentTypes[c.ContentType()]
+	}
+	if !models.ValidBulkFormat(options.Format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, json or ndjson"})
+		return
+	}
+	if value, ok := c.GetQuery("dry_run"); ok {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+			return
+		}
+		options.DryRun = dryRun
+	}
+
+	report, err := h.repo.ImportSongs(c, c.Request.Body, options)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to import songs: %v", err)
+		if report == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Batches committed before the file became unreadable stay imported.
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// bulkMediaTypes are the content types of exported files.
+var bulkMediaTypes = map[string]string{
+	models.FormatCSV:    "text/csv; charset=utf-8",
+	models.FormatJSON:   "application/json; charset=utf-8",
+	models.FormatNDJSON: "application/x-ndjson",
+}
+
+// @Summary Export songs in bulk
+// @Description Streams every live song, or those matching the filters, oldest first, as CSV (artists separated by ";"), a JSON array or NDJSON. Songs are written as they are read from the database. The filters are the same as for /api/songs/filtered
+// @Tags songs
+// @Produce plain
+// @Param format query string false "csv, json or ndjson" default(ndjson)
+// @Param lyrics query bool false "Include lyrics" default(true)
+// @Param name query string false "Song name contains (case-insensitive)"
+// @Param group query string false "Group name contains (case-insensitive)"
+// @Param group_id query string false "Group ID"
+// @Param artist query string false "Artist is one of the song's artists"
+// @Param genre query string false "Genre slug; songs in its subgenres match too"
+// @Param tag query []string false "Tag the song carries; repeat to require several" collectionFormat(multi)
+// @Param language query string false "Detected language of the song (en, ru or uz)"
+// @Param released_from query string false "Released on or after (YYYY-MM-DD or RFC 3339)"
+// @Param released_to query string false "Released on or before (YYYY-MM-DD or RFC 3339)"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD or RFC 3339)"
+// @Success 200 {string} string "songs.csv, songs.json or songs.ndjson"
+// @Failure 400 {object} map[string]string "unknown format or invalid filter"
+// @Failure 500 {object} map[string]string "failed to export songs"
+// @Router /api/export [get]
+func (h *Handler) ExportSongsHandler(c *gin.Context) {
+	filter, err := parseSongFilter(c, exportParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	options := models.ExportOptions{Format: c.DefaultQuery("format", models.FormatNDJSON), Filter: filter}
+	if !models.ValidBulkFormat(options.Format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, json or ndjson"})
+		return
+	}
+	if value, ok := c.GetQuery("lyrics"); ok {
+		lyrics, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lyrics"})
+			return
+		}
+		options.OmitLyrics = !lyrics
+	}
+
+	c.Header("Content-Type", bulkMediaTypes[options.Format])
+	c.Header("Content-Disposition", `attachment; filename="songs.`+options.Format+`"`)
+	if _, err := h.repo.ExportSongs(c, flushWriter{c.Writer}, options); err != nil {
+		h.logger.Printf("ERROR: Failed to export songs: %v", err)
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export songs"})
+		}
+		// Once streaming has started the status is sent; the client gets a truncated file.
+	}
+}
+
+// flushWriter sends every write to the client straight away, so a streamed
+// response is not held back by buffering middleware.
+type flushWriter struct {
+	w gin.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.w.Flush()
+	return n, err
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// ifMatch reads the If-Match header into the song versions a write may
+// replace. Without the header, or with "*", any version is allowed. Weak and
+// unrecognised tags never match, since If-Match uses strong comparison.
+func ifMatch(c *gin.Context) models.VersionCondition {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+	condition := models.VersionCondition{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		value, ok := strings.CutPrefix(strings.Trim(tag, `"`), "v")
+		if !ok {
+			continue
+		}
+		if version, err := strconv.Atoi(value); err == nil {
+			condition = append(condition, version)
+		}
+	}
+	return condition
+}
+
+// conditionalGet makes every successful read revalidatable. Handlers that know
+// their validators cheaply set them with notModified, often before doing the
+// expensive part of the work; for the rest the ETag is a hash of the response
+// body. A client whose copy is still current gets 304 Not Modified with no body.
+func (h *Handler) conditionalGet(c *gin.Context) {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		c.Next()
+		return
+	}
+
+	writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+	if writer.streaming {
+		return
+	}
+
+	header := writer.Header()
+	if writer.status == http.StatusOK || writer.status == http.StatusNotModified {
+		if h.cacheControl != "" && header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", h.cacheControl)
+		}
+	}
+	if writer.status == http.StatusOK {
+		if header.Get("ETag") == "" {
+			sum := sha256.Sum256(writer.body.Bytes())
+			header.Set("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+		}
+		lastModified, _ := http.ParseTime(header.Get("Last-Modified"))
+		if isNotModified(c.Request, header.Get("ETag"), lastModified) {
+			writer.status = http.StatusNotModified
+			writer.body.Reset()
+		}
+	}
+	if writer.status == http.StatusNotModified {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+	}
+
+	writer.ResponseWriter.WriteHeader(writer.status)
+	writer.ResponseWriter.WriteHeaderNow()
+	if writer.body.Len() > 0 {
+		writer.ResponseWriter.Write(writer.body.Bytes())
+	}
+}
+
+// notModified sets the ETag and, when known, Last-Modified of the resource
+// being read, and answers 304 if the client's copy is current. Handlers return
+// straight away when it reports true.
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if !isNotModified(c.Request, etag, lastModified) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// listingNotModified sets the validators of a song listing from a summary of
+// the songs matching filter, and answers 304 if the client's copy is current,
+// all before the page is loaded. When the summary cannot be read, the listing
+// falls back to the hash of its body.
+func (h *Handler) listingNotModified(c *gin.Context, filter models.SongFilter) bool {
+	state, err := h.repo.GetSongListingState(c, filter)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to summarize listing, validating it by its body: %v", err)
+		return false
+	}
+	lastModified := state.LastModified
+	if filter.Deleted {
+		// Purged songs leave no updated_at behind to move it forward.
+		lastModified = time.Time{}
+	}
+	return notModified(c, state.ETag(), lastModified)
+}
+
+// songNotModified sets the validators of a read of data that changes only
+// along with the song's version, such as its lyrics, genres, tags and
+// revisions, and answers 304 if the client's copy is current. Errors are left
+// to the handler, which reports them when it loads the data.
+func (h *Handler) songNotModified(c *gin.Context, id string) bool {
+	song, err := h.repo.GetSongByID(c, id)
+	if err != nil {
+		return false
+	}
+	return notModified(c, "W/"+song.ETag(), song.UpdatedAt)
+}
+
+// isNotModified evaluates If-None-Match, or If-Modified-Since when that is
+// absent, against the resource's current validators.
+func isNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		if strings.TrimSpace(header) == "*" {
+			return true
+		}
+		for _, tag := range strings.Split(header, ",") {
+			if weakEqual(strings.TrimSpace(tag), etag) {
+				return true
+			}
+		}
+		return false
+	}
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// weakEqual compares entity tags ignoring the weak prefix, as If-None-Match does.
+func weakEqual(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// bufferedWriter holds back a response until conditionalGet has compared it
+// with the client's validators. A handler that flushes, such as a streaming
+// download, switches it to writing straight through.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.streaming {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.streaming {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.streaming || w.body.Len() > 0
+}
+
+func (w *bufferedWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.WriteHeaderNow()
+		if w.body.Len() > 0 {
+			w.ResponseWriter.Write(w.body.Bytes())
+			w.body.Reset()
+		}
+	}
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/internal/repos"
+)
+
+// listingRepo serves one song and a summary of every listing, and records
+// which listings were loaded. Other repository calls panic.
+type listingRepo struct {
+	repos.Repo
+	state   models.ListingState
+	song    models.Song
+	filters []models.SongFilter
+	loaded  []string
+}
+
+func (r *listingRepo) GetSongListingState(_ context.Context, filter models.SongFilter) (*models.ListingState, error) {
+	r.filters = append(r.filters, filter)
+	return &r.state, nil
+}
+
+func (r *listingRepo) GetSongs(context.Context, models.PageRequest) (*models.SongPage, error) {
+	r.loaded = append(r.loaded, "songs")
+	return &models.SongPage{Songs: []models.Song{r.song}}, nil
+}
+
+func (r *listingRepo) GetSongsWithFilters(_ context.Context, filter models.SongFilter, _ models.PageRequest) (*models.SongPage, error) {
+	r.filters = append(r.filters, filter)
+	r.loaded = append(r.loaded, "filtered")
+	return &models.SongPage{Songs: []models.Song{}}, nil
+}
+
+func (r *listingRepo) GetTrash(context.Context, int, int) ([]models.Song, error) {
+	r.loaded = append(r.loaded, "trash")
+	return []models.Song{}, nil
+}
+
+func (r *listingRepo) GetSongByID(context.Context, string) (*models.Song, error) {
+	return &r.song, nil
+}
+
+func (r *listingRepo) GetSongTags(context.Context, string) ([]string, error) {
+	r.loaded = append(r.loaded, "tags")
+	return []string{"live"}, nil
+}
+
+func newConditionalRouter(repo repos.Repo) *gin.Engine {
+	h := &Handler{repo: repo, cacheControl: "private, no-cache", logger: log.New(io.Discard, "", 0)}
+	router := gin.New()
+	api := router.Group("/api", h.conditionalGet)
+	api.GET("/songs", h.GetSongsHandler)
+	api.GET("/trash", h.GetTrashHandler)
+	api.GET("/songs/:id/tags", h.GetSongTagsHandler)
+	return router
+}
+
+func serveGet(router *gin.Engine, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestListingValidators(t *testing.T) {
+	lastModified := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &listingRepo{state: models.ListingState{
+		Count:        2,
+		Versions:     5,
+		UpdatedAt:    lastModified.Add(-time.Hour),
+		LastModified: lastModified,
+	}}
+	router := newConditionalRouter(repo)
+
+	first := serveGet(router, "/api/songs", nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", first.Code, http.StatusOK)
+	}
+	etag := first.Header().Get("ETag")
+	if etag != repo.state.ETag() {
+		t.Errorf("ETag = %q, want the listing state's %q", etag, repo.state.ETag())
+	}
+	if got := first.Header().Get("Last-Modified"); got != lastModified.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want %q", got, lastModified.Format(http.TimeFormat))
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	for name, header := range map[string]http.Header{
+		"If-None-Match":     {"If-None-Match": {etag}},
+		"If-Modified-Since": {"If-Modified-Since": {lastModified.Format(http.TimeFormat)}},
+	} {
+		repo.loaded = nil
+		recorder := serveGet(router, "/api/songs", header)
+		if recorder.Code != http.StatusNotModified {
+			t.Errorf("%s: status = %d, want %d", name, recorder.Code, http.StatusNotModified)
+		}
+		if recorder.Body.Len() != 0 {
+			t.Errorf("%s: body = %q, want none", name, recorder.Body)
+		}
+		if len(repo.loaded) != 0 {
+			t.Errorf("%s: loaded %v, want the page left unread", name, repo.loaded)
+		}
+	}
+
+	// A new version of any listed song changes the ETag.
+	repo.state.Versions++
+	if recorder := serveGet(router, "/api/songs", http.Header{"If-None-Match": {etag}}); recorder.Code != http.StatusOK {
+		t.Errorf("status after a change = %d, want %d", recorder.Code, http.StatusOK)
+	}
+}
+
+func TestTrashValidatorsOmitLastModified(t *testing.T) {
+	repo := &listingRepo{state: models.ListingState{Count: 1, Versions: 1, LastModified: time.Now()}}
+	router := newConditionalRouter(repo)
+
+	recorder := serveGet(router, "/api/trash", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if got := recorder.Header().Get("Last-Modified"); got != "" {
+		t.Errorf("Last-Modified = %q, want none, since purges do not move it", got)
+	}
+	if len(repo.filters) != 1 || !repo.filters[0].Deleted {
+		t.Errorf("summarized filters %+v, want the deleted songs", repo.filters)
+	}
+}
+
+func TestSongSubresourceValidators(t *testing.T) {
+	updatedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &listingRepo{song: models.Song{ID: uuid.New(), Version: 4, UpdatedAt: updatedAt}}
+	router := newConditionalRouter(repo)
+	target := "/api/songs/" + repo.song.ID.String() + "/tags"
+
+	first := serveGet(router, target, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", first.Code, http.StatusOK)
+	}
+	if got := first.Header().Get("ETag"); got != `W/"v4"` {
+		t.Errorf("ETag = %q, want %q", got, `W/"v4"`)
+	}
+	var tags []string
+	if err := json.Unmarshal(first.Body.Bytes(), &tags); err != nil || len(tags) != 1 {
+		t.Errorf("body = %s, want the tags", first.Body)
+	}
+
+	repo.loaded = nil
+	if recorder := serveGet(router, target, http.Header{"If-None-Match": {`"v4"`}}); recorder.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusNotModified)
+	}
+	if len(repo.loaded) != 0 {
+		t.Errorf("loaded %v, want the tags left unread", repo.loaded)
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// songFilterParams lists every query parameter GET /api/songs/filtered understands.
+var songFilterParams = map[string]bool{
+	"name":          true,
+	"group":         true,
+	"group_id":      true,
+	"artist":        true,
+	"genre":         true,
+	"tag":           true,
+	"language":      true,
+	"released_from": true,
+	"released_to":   true,
+	"created_from":  true,
+	"created_to":    true,
+	"deleted":       true,
+	"limit":         true,
+	"sort":          true,
+	"cursor":        true,
+}
+
+// exportParams lists every query parameter GET /api/export understands. The
+// export always covers live songs only.
+var exportParams = map[string]bool{
+	"name":          true,
+	"group":         true,
+	"group_id":      true,
+	"artist":        true,
+	"genre":         true,
+	"tag":           true,
+	"language":      true,
+	"released_from": true,
+	"released_to":   true,
+	"created_from":  true,
+	"created_to":    true,
+	"format":        true,
+	"lyrics":        true,
+}
+
+// parseSongFilter builds a SongFilter from the query string, rejecting
+// parameters that are not in params.
+func parseSongFilter(c *gin.Context, params map[string]bool) (models.SongFilter, error) {
+	return models.ParseSongFilter(c.Request.URL.Query(), params)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testContext returns a gin context for a GET request to target.
+func testContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, recorder
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestParseSongFilter(t *testing.T) {
+	groupID := uuid.MustParse("7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23")
+	endOfDay := func(year int, month time.Month, day int) *time.Time {
+		t := time.Date(year, month, day, 23, 59, 59, 999999000, time.UTC)
+		return &t
+	}
+	instant := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		want  models.SongFilter
+	}{
+		{name: "empty", query: "", want: models.SongFilter{}},
+		{name: "name", query: "name=love", want: models.SongFilter{Name: "love"}},
+		{name: "group", query: "group=Muse", want: models.SongFilter{Group: "Muse"}},
+		{name: "group_id", query: "group_id=" + groupID.String(), want: models.SongFilter{GroupID: &groupID}},
+		{name: "artist", query: "artist=Queen", want: models.SongFilter{Artist: "Queen"}},
+		{name: "genre", query: "genre=rock", want: models.SongFilter{Genre: "rock"}},
+		{name: "tags", query: "tag=live&tag=sad", want: models.SongFilter{Tags: []string{"live", "sad"}}},
+		{name: "language is canonicalized", query: "language=RU", want: models.SongFilter{Language: "ru"}},
+		{name: "deleted", query: "deleted=true", want: models.SongFilter{Deleted: true}},
+		{
+			name:  "date bounds cover whole days",
+			query: "released_from=2020-01-01&released_to=2020-12-31&created_from=2024-05-01&created_to=2024-05-01",
+			want: models.SongFilter{
+				ReleasedFrom: date(2020, 1, 1),
+				ReleasedTo:   endOfDay(2020, 12, 31),
+				CreatedFrom:  date(2024, 5, 1),
+				CreatedTo:    endOfDay(2024, 5, 1),
+			},
+		},
+		{
+			name:  "timestamp bounds are exact",
+			query: "created_to=2024-05-01T12:30:00Z",
+			want:  models.SongFilter{CreatedTo: &instant},
+		},
+		{
+			name:  "combined",
+			query: "name=love&artist=Queen&tag=live&language=en&released_from=2020-01-01&limit=5&sort=-name",
+			want: models.SongFilter{
+				Name:         "love",
+				Artist:       "Queen",
+				Tags:         []string{"live"},
+				Language:     "en",
+				ReleasedFrom: date(2020, 1, 1),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := testContext("/api/songs/filtered?" + tt.query)
+			got, err := parseSongFilter(c, songFilterParams)
+			if err != nil {
+				t.Fatalf("parseSongFilter: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSongFilterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		params map[string]bool
+	}{
+		{name: "unknown parameter", query: "colour=red", params: songFilterParams},
+		{name: "deleted is not exportable", query: "deleted=true", params: exportParams},
+		{name: "invalid group_id", query: "group_id=42", params: songFilterParams},
+		{name: "invalid language", query: "language=!!", params: songFilterParams},
+		{name: "invalid deleted", query: "deleted=maybe", params: songFilterParams},
+		{name: "invalid date", query: "released_from=01.05.2024", params: songFilterParams},
+		{name: "reversed release range", query: "released_from=2021-01-01&released_to=2020-01-01", params: songFilterParams},
+		{name: "reversed creation range", query: "created_from=2021-01-01&created_to=2020-01-01", params: songFilterParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := testContext("/api/songs/filtered?" + tt.query)
+			if filter, err := parseSongFilter(c, tt.params); err == nil {
+				t.Errorf("parseSongFilter succeeded with %+v, want an error", filter)
+			}
+		})
+	}
+}
+
+func TestGetSongsWithFiltersUnknownParameter(t *testing.T) {
+	// The repository is never reached, so the handler needs none.
+	h := &Handler{logger: log.New(io.Discard, "", 0)}
+	c, recorder := testContext("/api/songs/filtered?artist=Queen&colour=red")
+
+	h.GetSongsWithFiltersHandler(c)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if want := `unknown filter "colour"`; body["error"] != want {
+		t.Errorf("error = %q, want %q", body["error"], want)
+	}
+}
+
+func TestDeletedFilterRequiresEditor(t *testing.T) {
+	tokens := auth.NewIssuer([]byte("secret"), time.Minute, time.Hour)
+	bearer := func(role string) http.Header {
+		pair, err := tokens.Issue(uuid.New(), role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return http.Header{"Authorization": {"Bearer " + pair.AccessToken}}
+	}
+	repo := &listingRepo{}
+	router := gin.New()
+	NewHandler(repo, tokens, nil, "", log.New(io.Discard, "", 0)).RegisterRoutes(router)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{name: "anonymous", want: http.StatusUnauthorized},
+		{name: "viewer", header: bearer(models.RoleViewer), want: http.StatusForbidden},
+		{name: "editor", header: bearer(models.RoleEditor), want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		repo.loaded = nil
+		recorder := serveGet(router, "/api/songs/filtered?deleted=true", tt.header)
+		if recorder.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, recorder.Code, tt.want)
+		}
+		if tt.want != http.StatusOK && len(repo.loaded) != 0 {
+			t.Errorf("%s: loaded %v, want the trash left unread", tt.name, repo.loaded)
+		}
+	}
+
+	// Live songs stay public.
+	if recorder := serveGet(router, "/api/songs/filtered?deleted=false", nil); recorder.Code != http.StatusOK {
+		t.Errorf("anonymous live listing: status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+}
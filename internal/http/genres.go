@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Create a genre
+// @Description Adds a genre, optionally under a parent genre. The slug is derived from the name
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body models.Genre true "Genre object"
+// @Success 201 {object} models.Genre
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "parent genre not found"
+// @Failure 409 {object} map[string]string "genre already exists"
+// @Failure 500 {object} map[string]string
+// @Router /api/genres [post]
+func (h *Handler) CreateGenreHandler(c *gin.Context) {
+	var genre models.Genre
+	if err := c.ShouldBindJSON(&genre); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	genre.ID = uuid.New()
+	genre.Slug = ""
+
+	if err := h.repo.CreateGenre(c, &genre); err != nil {
+		h.logger.Printf("ERROR: Failed to create genre: %v", err)
+		h.respondGenreError(c, err, "failed to create genre")
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
+
+// @Summary Get the genre tree
+// @Description Fetches all genres as a tree of top-level genres with their subgenres nested under children
+// @Tags genres
+// @Produce json
+// @Success 200 {array} models.Genre
+// @Failure 500 {object} map[string]string "failed to fetch genres"
+// @Router /api/genres [get]
+func (h *Handler) GetGenresHandler(c *gin.Context) {
+	genres, err := h.repo.GetGenres(c)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch genres: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch genres"})
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// @Summary Get a genre by ID
+// @Description Fetches a genre with its direct subgenres
+// @Tags genres
+// @Produce json
+// @Param id path string true "Genre ID"
+// @Success 200 {object} models.Genre
+// @Failure 404 {object} map[string]string
+// @Router /api/genres/{id} [get]
+func (h *Handler) GetGenreByIDHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	genre, err := h.repo.GetGenreByID(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch genre ID %s: %v", id, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "genre not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// @Summary Update a genre
+// @Description Renames a genre or moves it under another parent. A genre cannot be moved under itself or one of its subgenres
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path string true "Genre ID"
+// @Param genre body models.Genre true "Genre data"
+// @Success 200 {object} models.Genre
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "genre not found"
+// @Failure 409 {object} map[string]string "genre already exists or would become its own ancestor"
+// @Failure 500 {object} map[string]string "failed to update genre"
+// @Router /api/genres/{id} [put]
+func (h *Handler) UpdateGenreHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid genre ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid genre ID"})
+		return
+	}
+
+	var genre models.Genre
+	if err := c.ShouldBindJSON(&genre); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	genre.ID = id
+	genre.Slug = ""
+
+	if err := h.repo.UpdateGenre(c, &genre); err != nil {
+		h.logger.Printf("ERROR: Failed to update genre ID %s: %v", id, err)
+		h.respondGenreError(c, err, "failed to update genre")
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// @Summary Delete a genre
+// @Description Deletes a genre that has no subgenres. Songs classified under it lose that genre
+// @Tags genres
+// @Param id path string true "Genre ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "genre not found"
+// @Failure 409 {object} map[string]string "genre has subgenres"
+// @Failure 500 {object} map[string]string
+// @Router /api/genres/{id} [delete]
+func (h *Handler) DeleteGenreHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeleteGenre(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete genre ID %s: %v", id, err)
+		h.respondGenreError(c, err, "failed to delete genre")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "genre deleted"})
+}
+
+// @Summary Get a song's genres
+// @Tags songs
+// @Produce json
+// @Param id path string true "Song ID"
+// @Success 200 {array} models.Genre
+// @Success 304 "The client's copy is current"
+// @Failure 500 {object} map[string]string "failed to fetch genres"
+// @Router /api/songs/{id}/genres [get]
+func (h *Handler) GetSongGenresHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	genres, err := h.repo.GetSongGenres(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch genres of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch genres"})
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// @Summary Set a song's genres
+// @Description Replaces the genres the song is classified under
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param genre_ids body []string true "Genre IDs"
+// @Success 200 {array} models.Genre
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "song or genre not found"
+// @Failure 500 {object} map[string]string "failed to set genres"
+// @Router /api/songs/{id}/genres [put]
+func (h *Handler) SetSongGenresHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var genreIDs []uuid.UUID
+	if err := c.ShouldBindJSON(&genreIDs); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	genres, err := h.repo.SetSongGenres(c, id, genreIDs)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set genres of song ID %s: %v", id, err)
+		h.respondGenreError(c, err, "failed to set genres")
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// @Summary Get a song's tags
+// @Tags songs
+// @Produce json
+// @Param id path string true "Song ID"
+// @Success 200 {array} string
+// @Success 304 "The client's copy is current"
+// @Failure 500 {object} map[string]string "failed to fetch tags"
+// @Router /api/songs/{id}/tags [get]
+func (h *Handler) GetSongTagsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	tags, err := h.repo.GetSongTags(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch tags of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary Set a song's tags
+// @Description Replaces the song's tags. Tags are free-form, stored lowercase, and created on first use
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param tags body []string true "Tags"
+// @Success 200 {array} string
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "song not found"
+// @Failure 500 {object} map[string]string "failed to set tags"
+// @Router /api/songs/{id}/tags [put]
+func (h *Handler) SetSongTagsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var tags []string
+	if err := c.ShouldBindJSON(&tags); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	tags, err := h.repo.SetSongTags(c, id, tags)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set tags of song ID %s: %v", id, err)
+		h.respondGenreError(c, err, "failed to set tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// respondGenreError maps genre and tag errors to a status code
+func (h *Handler) respondGenreError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrDuplicateGenre), errors.Is(err, models.ErrGenreHasChildren), errors.Is(err, models.ErrGenreCycle):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case isNotFound(err), isForeignKeyViolation(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
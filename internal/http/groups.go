@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Create a group
+// @Description Adds a new group. Names are unique regardless of case and whitespace
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param group body models.Group true "Group object"
+// @Success 201 {object} models.Group
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string "group already exists"
+// @Failure 500 {object} map[string]string
+// @Router /api/groups [post]
+func (h *Handler) CreateGroupHandler(c *gin.Context) {
+	var group models.Group
+	if err := c.ShouldBindJSON(&group); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if group.FormedOn != nil && group.DissolvedOn != nil && group.DissolvedOn.Before(*group.FormedOn) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dissolved_on must not be before formed_on"})
+		return
+	}
+	group.ID = uuid.New()
+
+	if err := h.repo.CreateGroup(c, &group); err != nil {
+		h.logger.Printf("ERROR: Failed to create group: %v", err)
+		h.respondGroupError(c, err, "failed to create group")
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// @Summary Get all groups
+// @Description Fetches groups ordered by name
+// @Tags groups
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.Group
+// @Failure 500 {object} map[string]string "failed to fetch groups"
+// @Router /api/groups [get]
+func (h *Handler) GetGroupsHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	groups, err := h.repo.GetGroups(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch groups: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// @Summary Get a group by ID
+// @Tags groups
+// @Produce json
+// @Param id path string true "Group ID"
+// @Success 200 {object} models.Group
+// @Failure 404 {object} map[string]string
+// @Router /api/groups/{id} [get]
+func (h *Handler) GetGroupByIDHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	group, err := h.repo.GetGroupByID(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch group ID %s: %v", id, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Get a group's catalogue and line-up
+// @Description Fetches the group's members on the given date and a page of the songs it had released by then
+// @Tags groups
+// @Produce json
+// @Param id path string true "Group ID"
+// @Param as_of query string false "Date (YYYY-MM-DD or RFC 3339), defaults to today"
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param sort query string false "Sort key: created_at, release_date or name, prefixed with - for descending" default(created_at)
+// @Param cursor query string false "next_cursor or prev_cursor from a previous page"
+// @Success 200 {object} models.GroupCatalogue
+// @Failure 400 {object} map[string]string "invalid date or cursor"
+// @Failure 404 {object} map[string]string "group not found"
+// @Failure 500 {object} map[string]string "failed to fetch catalogue"
+// @Router /api/groups/{id}/catalogue [get]
+func (h *Handler) GetGroupCatalogueHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	asOf, err := models.ParseFilterTime(c.Request.URL.Query(), "as_of")
+	if err != nil {
+		h.logger.Printf("ERROR: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if asOf == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		asOf = &today
+	}
+
+	catalogue, err := h.repo.GetGroupCatalogue(c, id, *asOf, getPageRequest(c))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch catalogue of group ID %s: %v", id, err)
+		if isNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return
+		}
+		h.respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, catalogue)
+}
+
+// @Summary Update a group
+// @Description Updates a group. Renaming also renames the group on its songs
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID"
+// @Param group body models.Group true "Group data"
+// @Success 200 {object} models.Group
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "group not found"
+// @Failure 409 {object} map[string]string "group already exists"
+// @Failure 500 {object} map[string]string "failed to update group"
+// @Router /api/groups/{id} [put]
+func (h *Handler) UpdateGroupHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid group ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		return
+	}
+
+	var group models.Group
+	if err := c.ShouldBindJSON(&group); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if group.FormedOn != nil && group.DissolvedOn != nil && group.DissolvedOn.Before(*group.FormedOn) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dissolved_on must not be before formed_on"})
+		return
+	}
+	group.ID = id
+
+	if err := h.repo.UpdateGroup(c, &group); err != nil {
+		h.logger.Printf("ERROR: Failed to update group ID %s: %v", id, err)
+		h.respondGroupError(c, err, "failed to update group")
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Delete a group
+// @Description Deletes a group and its membership history when no song references it
+// @Tags groups
+// @Param id path string true "Group ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "group not found"
+// @Failure 409 {object} map[string]string "group is referenced by songs"
+// @Failure 500 {object} map[string]string
+// @Router /api/groups/{id} [delete]
+func (h *Handler) DeleteGroupHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeleteGroup(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete group ID %s: %v", id, err)
+		h.respondGroupError(c, err, "failed to delete group")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "group deleted"})
+}
+
+// @Summary Get a group's membership history
+// @Description Fetches every membership of the group, earliest first
+// @Tags groups
+// @Produce json
+// @Param id path string true "Group ID"
+// @Success 200 {array} models.GroupMember
+// @Failure 500 {object} map[string]string "failed to fetch members"
+// @Router /api/groups/{id}/members [get]
+func (h *Handler) GetGroupMembersHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	members, err := h.repo.GetGroupMembers(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch members of group ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Add a member to a group
+// @Description Records that an artist was a member of the group from joined_on until left_on
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID"
+// @Param member body models.GroupMember true "Membership"
+// @Success 201 {object} models.GroupMember
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "group or artist not found"
+// @Failure 500 {object} map[string]string "failed to add member"
+// @Router /api/groups/{id}/members [post]
+func (h *Handler) AddGroupMemberHandler(c *gin.Context) {
+	member, ok := h.bindGroupMember(c)
+	if !ok {
+		return
+	}
+	member.ID = uuid.New()
+
+	if err := h.repo.AddGroupMember(c, member); err != nil {
+		h.logger.Printf("ERROR: Failed to add member to group ID %s: %v", member.GroupID, err)
+		h.respondGroupError(c, err, "failed to add member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// @Summary Update a group membership
+// @Description Updates the artist, role or dates of a membership, e.g. to record that the artist left
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID"
+// @Param member_id path string true "Membership ID"
+// @Param member body models.GroupMember true "Membership"
+// @Success 200 {object} models.GroupMember
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 404 {object} map[string]string "membership not found"
+// @Failure 500 {object} map[string]string "failed to update member"
+// @Router /api/groups/{id}/members/{member_id} [put]
+func (h *Handler) UpdateGroupMemberHandler(c *gin.Context) {
+	memberID, err := uuid.Parse(c.Param("member_id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid membership ID %s: %v", c.Param("member_id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid membership ID"})
+		return
+	}
+
+	member, ok := h.bindGroupMember(c)
+	if !ok {
+		return
+	}
+	member.ID = memberID
+
+	if err := h.repo.UpdateGroupMember(c, member); err != nil {
+		h.logger.Printf("ERROR: Failed to update membership ID %s: %v", memberID, err)
+		h.respondGroupError(c, err, "failed to update member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Remove a group membership
+// @Description Deletes a membership entered by mistake. To record that an artist left, set left_on instead
+// @Tags groups
+// @Param id path string true "Group ID"
+// @Param member_id path string true "Membership ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "membership not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/groups/{id}/members/{member_id} [delete]
+func (h *Handler) DeleteGroupMemberHandler(c *gin.Context) {
+	groupID, memberID := c.Param("id"), c.Param("member_id")
+
+	if err := h.repo.DeleteGroupMember(c, groupID, memberID); err != nil {
+		h.logger.Printf("ERROR: Failed to delete membership ID %s: %v", memberID, err)
+		h.respondGroupError(c, err, "failed to delete member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// bindGroupMember parses and validates a membership body for the group in the path
+func (h *Handler) bindGroupMember(c *gin.Context) (*models.GroupMember, bool) {
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid group ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		return nil, false
+	}
+
+	var member models.GroupMember
+	if err := c.ShouldBindJSON(&member); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return nil, false
+	}
+	if member.JoinedOn != nil && member.LeftOn != nil && !member.LeftOn.After(*member.JoinedOn) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "left_on must be after joined_on"})
+		return nil, false
+	}
+	member.GroupID = groupID
+	return &member, true
+}
+
+// respondGroupError maps group errors to a status code
+func (h *Handler) respondGroupError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrDuplicateGroup), errors.Is(err, models.ErrGroupInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case isNotFound(err), isForeignKeyViolation(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,55 +14,169 @@ import (
 	_ "github.com/ruziba3vich/music_lib/docs"
 	"github.com/ruziba3vich/music_lib/internal/models"
 	"github.com/ruziba3vich/music_lib/internal/repos"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
+	"github.com/ruziba3vich/music_lib/pkg/cursor"
+	"github.com/ruziba3vich/music_lib/pkg/jsonpatch"
+	"github.com/ruziba3vich/music_lib/pkg/ratelimit"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
-	repo   repos.Repo
-	logger *log.Logger
+	repo         repos.Repo
+	tokens       *auth.Issuer
+	limiter      *ratelimit.Limiter
+	cacheControl string
+	logger       *log.Logger
 }
 
-func NewHandler(repo repos.Repo, logger *log.Logger) *Handler {
+// NewHandler creates the HTTP layer. A nil limiter disables rate limiting;
+// cacheControl is sent on successful reads unless it is empty.
+func NewHandler(repo repos.Repo, tokens *auth.Issuer, limiter *ratelimit.Limiter, cacheControl string, logger *log.Logger) *Handler {
 
 	return &Handler{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		tokens:       tokens,
+		limiter:      limiter,
+		cacheControl: cacheControl,
+		logger:       logger,
 	}
 }
 
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	api := router.Group("/api")
+	api := router.Group("/api", h.authenticate, h.rateLimit, h.conditionalGet)
+	editor := h.requireRole(models.RoleEditor)
+	admin := h.requireRole(models.RoleAdmin)
 	{
-		api.POST("/songs", h.CreateSongHandler)
+		api.POST("/auth/register", h.RegisterHandler)
+		api.POST("/auth/login", h.LoginHandler)
+		api.POST("/auth/refresh", h.RefreshHandler)
+		api.GET("/auth/me", h.requireUser, h.MeHandler)
+
+		api.GET("/users", admin, h.GetUsersHandler)
+		api.PUT("/users/:id/role", admin, h.SetUserRoleHandler)
+
+		api.POST("/api-keys", admin, h.CreateAPIKeyHandler)
+		api.GET("/api-keys", admin, h.GetAPIKeysHandler)
+		api.DELETE("/api-keys/:id", admin, h.RevokeAPIKeyHandler)
+
+		api.POST("/songs", editor, h.CreateSongHandler)
+		api.POST("/songs/import", editor, h.ImportSongsHandler)
 		api.GET("/songs/filtered", h.GetSongsWithFiltersHandler)
+		api.GET("/export", editor, h.ExportSongsHandler)
 		api.GET("/songs", h.GetSongsHandler)
 		api.GET("/songs/:id", h.GetSongByIDHandler)
 		api.GET("/songs/:id/lyrics", h.GetSongLyricsPaginatedHandler)
+		api.GET("/songs/:id/lyrics/sections", h.GetSongLyricsSectionsHandler)
+		api.GET("/songs/:id/lyrics/sections/:index", h.GetSongLyricsSectionHandler)
+		api.GET("/songs/:id/lyrics/lrc", h.GetSyncedLyricsHandler)
+		api.PUT("/songs/:id/lyrics/lrc", editor, h.SetSyncedLyricsHandler)
+		api.DELETE("/songs/:id/lyrics/lrc", editor, h.DeleteSyncedLyricsHandler)
+		api.GET("/songs/:id/lyrics/at", h.GetLyricsAtHandler)
+		api.GET("/songs/:id/lyrics/translated", h.GetTranslatedLyricsPaginatedHandler)
+		api.GET("/songs/:id/translations", h.GetSongTranslationsHandler)
+		api.GET("/songs/:id/translations/:language", h.GetSongTranslationHandler)
+		api.PUT("/songs/:id/translations/:language", editor, h.SetSongTranslationHandler)
+		api.DELETE("/songs/:id/translations/:language", editor, h.DeleteSongTranslationHandler)
 		api.GET("/songs/artists", h.GetSongsByArtistHandler)
-		api.PUT("/songs/:id", h.UpdateSongHandler)
-		api.DELETE("/songs/:id", h.DeleteSongHandler)
+		api.PUT("/songs/:id", editor, h.UpdateSongHandler)
+		api.PATCH("/songs/:id", editor, h.PatchSongHandler)
+		api.DELETE("/songs/:id", editor, h.DeleteSongHandler)
+		api.GET("/songs/:id/artists", h.GetSongArtistsHandler)
+		api.PUT("/songs/:id/artists", editor, h.SetSongArtistsHandler)
+		api.GET("/songs/:id/genres", h.GetSongGenresHandler)
+		api.PUT("/songs/:id/genres", editor, h.SetSongGenresHandler)
+		api.GET("/songs/:id/tags", h.GetSongTagsHandler)
+		api.PUT("/songs/:id/tags", editor, h.SetSongTagsHandler)
+		api.GET("/songs/:id/revisions", h.GetSongRevisionsHandler)
+		api.GET("/songs/:id/revisions/diff", h.DiffSongRevisionsHandler)
+		api.GET("/songs/:id/revisions/:number", h.GetSongRevisionHandler)
+		api.POST("/songs/:id/revisions/:number/rollback", editor, h.RollbackSongHandler)
+		api.POST("/songs/:id/restore", editor, h.RestoreSongHandler)
+		api.GET("/search", h.SearchSongsHandler)
+
+		api.GET("/trash", editor, h.GetTrashHandler)
+		api.DELETE("/trash/:id", admin, h.PurgeSongHandler)
+
+		api.POST("/artists", editor, h.CreateArtistHandler)
+		api.GET("/artists", h.GetArtistsHandler)
+		api.GET("/artists/:id", h.GetArtistByIDHandler)
+		api.GET("/artists/:id/songs", h.GetSongsByArtistIDHandler)
+		api.PUT("/artists/:id", editor, h.UpdateArtistHandler)
+		api.DELETE("/artists/:id", editor, h.DeleteArtistHandler)
+
+		api.POST("/groups", editor, h.CreateGroupHandler)
+		api.GET("/groups", h.GetGroupsHandler)
+		api.GET("/groups/:id", h.GetGroupByIDHandler)
+		api.GET("/groups/:id/catalogue", h.GetGroupCatalogueHandler)
+		api.PUT("/groups/:id", editor, h.UpdateGroupHandler)
+		api.DELETE("/groups/:id", editor, h.DeleteGroupHandler)
+		api.GET("/groups/:id/members", h.GetGroupMembersHandler)
+		api.POST("/groups/:id/members", editor, h.AddGroupMemberHandler)
+		api.PUT("/groups/:id/members/:member_id", editor, h.UpdateGroupMemberHandler)
+		api.DELETE("/groups/:id/members/:member_id", editor, h.DeleteGroupMemberHandler)
+
+		api.POST("/genres", editor, h.CreateGenreHandler)
+		api.GET("/genres", h.GetGenresHandler)
+		api.GET("/genres/:id", h.GetGenreByIDHandler)
+		api.PUT("/genres/:id", editor, h.UpdateGenreHandler)
+		api.DELETE("/genres/:id", editor, h.DeleteGenreHandler)
+
+		api.POST("/playlists", h.requireUser, h.CreatePlaylistHandler)
+		api.GET("/playlists", h.GetPlaylistsHandler)
+		api.GET("/playlists/:id", h.GetPlaylistByIDHandler)
+		api.PUT("/playlists/:id", h.requireUser, h.UpdatePlaylistHandler)
+		api.DELETE("/playlists/:id", h.requireUser, h.DeletePlaylistHandler)
+		api.POST("/playlists/:id/entries", h.requireUser, h.AddPlaylistEntryHandler)
+		api.POST("/playlists/:id/entries/:entry_id/move", h.requireUser, h.MovePlaylistEntryHandler)
+		api.DELETE("/playlists/:id/entries/:entry_id", h.requireUser, h.RemovePlaylistEntryHandler)
+
+		api.POST("/albums", editor, h.CreateAlbumHandler)
+		api.GET("/albums", h.GetAlbumsHandler)
+		api.GET("/albums/:id", h.GetAlbumByIDHandler)
+		api.PUT("/albums/:id", editor, h.UpdateAlbumHandler)
+		api.DELETE("/albums/:id", editor, h.DeleteAlbumHandler)
+
+		api.POST("/releases", editor, h.CreateReleaseHandler)
+		api.GET("/releases", h.GetReleasesHandler)
+		api.GET("/releases/:id", h.GetReleaseByIDHandler)
+		api.PUT("/releases/:id", editor, h.UpdateReleaseHandler)
+		api.DELETE("/releases/:id", editor, h.DeleteReleaseHandler)
+		api.PUT("/releases/:id/tracks", editor, h.SetReleaseTracksHandler)
+		api.POST("/releases/:id/tracks", editor, h.AddReleaseTrackHandler)
+		api.POST("/releases/:id/tracks/:track_id/move", editor, h.MoveReleaseTrackHandler)
+		api.DELETE("/releases/:id/tracks/:track_id", editor, h.RemoveReleaseTrackHandler)
 	}
 }
 
 // @Summary Create a new song
-// @Description Adds a new song to the database
+// @Description Adds a new song to the database. Missing release date, lyrics and link are fetched from the external info API
 // @Accept json
 // @Tags songs
 // @Produce json
 // @Param song body models.Song true "Song object"
 // @Success 201 {object} models.Song
 // @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string "Invalid song"
 // @Failure 500 {object} map[string]string
 // @Router /api/songs [post]
 func (h *Handler) CreateSongHandler(c *gin.Context) {
-	var song models.Song
-	if err := c.ShouldBindJSON(&song); err != nil {
+	var body models.Song
+	if err := c.ShouldBindJSON(&body); err != nil {
 		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
+	// Only the editable fields come from the client; a new song starts at
+	// the first version, outside the trash.
+	var song models.Song
+	song.ApplyState(body.State())
+	if err := song.State().Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Generate a time-based UUID
 	timestamp := time.Now().UnixNano()
@@ -76,11 +192,16 @@ func (h *Handler) CreateSongHandler(c *gin.Context) {
 }
 
 // @Summary Get a song by ID
-// @Description Fetches a song from the database using its ID
+// @Description Fetches a song from the database using its ID, with its genres, tags and the releases it appears on. The ETag names the song's version; send it back in If-Match when writing to detect concurrent edits
 // @Produce json
 // @Tags songs
 // @Param id path string true "Song ID"
-// @Success 200 {object} models.Song
+// @Param If-None-Match header string false "ETag of the copy the client holds"
+// @Param If-Modified-Since header string false "Last-Modified of the copy the client holds"
+// @Success 200 {object} models.SongDetails
+// @Success 304 "The client's copy is current"
+// @Header 200 {string} ETag "Version of the song"
+// @Header 200 {string} Last-Modified "When the song last changed"
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/songs/{id} [get]
@@ -93,44 +214,81 @@ func (h *Handler) GetSongByIDHandler(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
 		return
 	}
+	if notModified(c, song.ETag(), song.UpdatedAt) {
+		return
+	}
 
-	c.JSON(http.StatusOK, song)
+	genres, err := h.repo.GetSongGenres(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch genres of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song genres"})
+		return
+	}
+
+	tags, err := h.repo.GetSongTags(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch tags of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song tags"})
+		return
+	}
+
+	releases, err := h.repo.GetSongReleases(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch releases of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch song releases"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SongDetails{Song: *song, Genres: genres, Tags: tags, Releases: releases})
 }
 
 // GetSongsWithFiltersHandler handles fetching songs with filters and pagination
 // @Summary Get songs with filters and pagination
-// @Description Fetches songs based on filters provided as query parameters
+// @Description Fetches songs based on filters provided as query parameters. tag_facets counts the tags across all matching songs
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param name query string false "Filter by song name"
-// @Param artist query string false "Filter by artist name"
-// @Param genre query string false "Filter by genre"
+// @Param name query string false "Song name contains (case-insensitive)"
+// @Param group query string false "Group name contains (case-insensitive)"
+// @Param group_id query string false "Group ID"
+// @Param artist query string false "Artist is one of the song's artists"
+// @Param genre query string false "Genre slug; songs in its subgenres match too"
+// @Param tag query []string false "Tag the song carries; repeat to require several" collectionFormat(multi)
+// @Param language query string false "Detected language of the song (en, ru or uz)"
+// @Param released_from query string false "Released on or after (YYYY-MM-DD or RFC 3339)"
+// @Param released_to query string false "Released on or before (YYYY-MM-DD or RFC 3339)"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD or RFC 3339)"
+// @Param deleted query bool false "Return soft-deleted songs instead of live ones (editor)" default(false)
 // @Param limit query int false "Limit the number of results" default(10)
-// @Param offset query int false "Offset for pagination" default(0)
-// @Success 200 {array} models.Song
+// @Param sort query string false "Sort key: created_at, release_date or name, prefixed with - for descending" default(created_at)
+// @Param cursor query string false "next_cursor or prev_cursor from a previous page"
+// @Success 200 {object} models.SongPage
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid filter or cursor"
+// @Failure 401 {object} map[string]string "deleted=true without authentication"
+// @Failure 403 {object} map[string]string "deleted=true below the editor role"
 // @Failure 500 {object} map[string]string "failed to fetch songs"
 // @Router /api/songs/filtered [get]
 func (h *Handler) GetSongsWithFiltersHandler(c *gin.Context) {
-	filters := map[string]any{}
-
-	if name := c.Query("name"); name != "" {
-		filters["name"] = name
+	filter, err := parseSongFilter(c, songFilterParams)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to parse filters: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if artist := c.Query("artist"); artist != "" {
-		filters["artist"] = artist
+	// Deleted songs are in the trash, which only editors may see.
+	if filter.Deleted && !hasRole(c, models.RoleEditor) {
+		return
 	}
-	if genre := c.Query("genre"); genre != "" {
-		filters["genre"] = genre
+	if h.listingNotModified(c, filter) {
+		return
 	}
 
-	limit := getIntQueryParam(c, "limit", 10)
-	offset := getIntQueryParam(c, "offset", 0)
-
-	songs, err := h.repo.GetSongsWithFilters(c, filters, limit, offset)
+	songs, err := h.repo.GetSongsWithFilters(c, filter, getPageRequest(c))
 	if err != nil {
 		h.logger.Printf("ERROR: Failed to fetch songs: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
+		h.respondListError(c, err)
 		return
 	}
 
@@ -138,23 +296,26 @@ func (h *Handler) GetSongsWithFiltersHandler(c *gin.Context) {
 }
 
 // @Summary Get all songs
-// @Description Fetches a list of songs with optional pagination
+// @Description Fetches a list of songs with optional pagination. tag_facets counts the tags across all songs
 // @Produce json
 // @Tags songs
 // @Param limit query int false "Limit the number of results" default(10)
-// @Param offset query int false "Offset for pagination" default(0)
-// @Success 200 {array} models.Song
+// @Param sort query string false "Sort key: created_at, release_date or name, prefixed with - for descending" default(created_at)
+// @Param cursor query string false "next_cursor or prev_cursor from a previous page"
+// @Success 200 {object} models.SongPage
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid cursor"
 // @Failure 500 {object} map[string]string "failed to fetch songs"
 // @Router /api/songs [get]
 func (h *Handler) GetSongsHandler(c *gin.Context) {
+	if h.listingNotModified(c, models.SongFilter{}) {
+		return
+	}
 
-	limit := getIntQueryParam(c, "limit", 10)
-	offset := getIntQueryParam(c, "offset", 0)
-
-	songs, err := h.repo.GetSongs(c, limit, offset)
+	songs, err := h.repo.GetSongs(c, getPageRequest(c))
 	if err != nil {
 		h.logger.Printf("ERROR: Failed to fetch songs: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
+		h.respondListError(c, err)
 		return
 	}
 
@@ -162,13 +323,14 @@ func (h *Handler) GetSongsHandler(c *gin.Context) {
 }
 
 // @Summary Get song lyrics with pagination
-// @Description Fetches paginated lyrics for a song by ID
+// @Description Fetches paginated lyrics for a song by ID, one entry per section with labels left off and repeated sections written out
 // @Produce json
 // @Tags songs
 // @Param id path string true "Song ID"
 // @Param limit query int false "Limit the number of results" default(10)
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {array} string
+// @Success 304 "The client's copy is current"
 // @Failure 500 {object} map[string]string "failed to fetch lyrics"
 // @Router /api/songs/{id}/lyrics [get]
 func (h *Handler) GetSongLyricsPaginatedHandler(c *gin.Context) {
@@ -176,6 +338,10 @@ func (h *Handler) GetSongLyricsPaginatedHandler(c *gin.Context) {
 	limit := getIntQueryParam(c, "limit", 10)
 	offset := getIntQueryParam(c, "offset", 0)
 
+	if h.songNotModified(c, id) {
+		return
+	}
+
 	lyrics, err := h.repo.GetSongLyricsPaginated(c, id, limit, offset)
 	if err != nil {
 		h.logger.Printf("ERROR: Failed to fetch lyrics for song ID %s: %v", id, err)
@@ -188,44 +354,124 @@ func (h *Handler) GetSongLyricsPaginatedHandler(c *gin.Context) {
 
 // UpdateSongHandler handles updating a song
 // @Summary Update a song
-// @Description Update the details of an existing song
+// @Description Replaces all editable fields of a song. Fields left out of the body are cleared; use PATCH to change only some. The previous state is kept as a revision
 // @Tags songs
 // @Accept json
 // @Produce json
+// @Param id path string true "Song ID"
 // @Param song body models.Song true "Song data"
+// @Param If-Match header string false "ETag of the version being replaced; the write fails with 412 if the song has changed since"
 // @Success 200 {object} models.Song
-// @Failure 400 {object} map[string]string "Invalid request body"
+// @Header 200 {string} ETag "Version of the song"
+// @Failure 400 {object} map[string]string "Invalid song ID or request body"
+// @Failure 404 {object} map[string]string "Song or group not found"
+// @Failure 412 {object} map[string]string "Song was changed since the given version"
+// @Failure 422 {object} map[string]string "Invalid song"
 // @Failure 500 {object} map[string]string "Failed to update song"
 // @Router /api/songs/{id} [put]
 func (h *Handler) UpdateSongHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid song ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid song ID"})
+		return
+	}
+
 	var song models.Song
 	if err := c.ShouldBindJSON(&song); err != nil {
 		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
+	song.ID = id
+	if err := song.State().Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := h.repo.UpdateSong(c, &song); err != nil {
-		h.logger.Printf("ERROR: Failed to update song: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update song"})
+	if err := h.repo.UpdateSong(c, &song, currentAuthor(c), ifMatch(c)); err != nil {
+		h.logger.Printf("ERROR: Failed to update song ID %s: %v", id, err)
+		h.respondSongError(c, err, "failed to update song")
+		return
+	}
+
+	c.Header("ETag", song.ETag())
+	c.JSON(http.StatusOK, song)
+}
+
+// @Summary Patch a song
+// @Description Changes some fields of a song. Send a JSON Merge Patch (RFC 7396) as application/merge-patch+json, or a JSON Patch (RFC 6902) as application/json-patch+json. The patch applies to the editable fields (name, group, group_id, artists, lyrics, link, release_date); the result is validated and saved in one transaction, and the previous state is kept as a revision
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Param If-Match header string false "ETag of the version being replaced; the write fails with 412 if the song has changed since"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Version of the song"
+// @Failure 400 {object} map[string]string "Invalid song ID or malformed patch"
+// @Failure 404 {object} map[string]string "Song or group not found"
+// @Failure 409 {object} map[string]string "A test operation failed"
+// @Failure 412 {object} map[string]string "Song was changed since the given version"
+// @Failure 415 {object} map[string]string "Unsupported patch format"
+// @Failure 422 {object} map[string]string "Patch cannot be applied or the result is invalid"
+// @Failure 500 {object} map[string]string "Failed to patch song"
+// @Router /api/songs/{id} [patch]
+func (h *Handler) PatchSongHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid song ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid song ID"})
+		return
+	}
+
+	patchType := c.ContentType()
+	if patchType != jsonpatch.MergePatchType && patchType != jsonpatch.JSONPatchType {
+		c.Header("Accept-Patch", jsonpatch.MergePatchType+", "+jsonpatch.JSONPatchType)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "patch must be " + jsonpatch.MergePatchType + " or " + jsonpatch.JSONPatchType})
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to read request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	song, err := h.repo.PatchSong(c, id.String(), patchType, patch, currentAuthor(c), ifMatch(c))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to patch song ID %s: %v", id, err)
+		h.respondSongError(c, err, "failed to patch song")
 		return
 	}
 
+	c.Header("ETag", song.ETag())
 	c.JSON(http.StatusOK, song)
 }
 
 // @Summary Delete a song
-// @Description Deletes a song by ID
+// @Description Moves a song to the trash, where it can be restored until it is purged
 // @Tags songs
 // @Param id path string true "Song ID"
+// @Param If-Match header string false "ETag of the version being replaced; the write fails with 412 if the song has changed since"
 // @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "song not found"
+// @Failure 412 {object} map[string]string "song was changed since the given version"
 // @Failure 500 {object} map[string]string
 // @Router /api/songs/{id} [delete]
 func (h *Handler) DeleteSongHandler(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.repo.DeleteSong(c, id); err != nil {
+	if err := h.repo.DeleteSong(c, id, ifMatch(c)); err != nil {
 		h.logger.Printf("ERROR: Failed to delete song ID %s: %v", id, err)
+		if errors.Is(err, models.ErrVersionMismatch) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		if isNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete song"})
 		return
 	}
@@ -233,6 +479,83 @@ func (h *Handler) DeleteSongHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "song deleted"})
 }
 
+// @Summary Get the trash
+// @Description Lists deleted songs, most recently deleted first. Songs are purged for good once they have been in the trash for the retention period
+// @Tags trash
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.Song
+// @Success 304 "The client's copy is current"
+// @Failure 500 {object} map[string]string "failed to fetch trash"
+// @Router /api/trash [get]
+func (h *Handler) GetTrashHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+	if h.listingNotModified(c, models.SongFilter{Deleted: true}) {
+		return
+	}
+
+	songs, err := h.repo.GetTrash(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch trash: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, songs)
+}
+
+// @Summary Restore a song
+// @Description Takes a song out of the trash
+// @Tags trash
+// @Produce json
+// @Param id path string true "Song ID"
+// @Success 200 {object} models.Song
+// @Failure 404 {object} map[string]string "song is not in the trash"
+// @Failure 500 {object} map[string]string "failed to restore song"
+// @Router /api/songs/{id}/restore [post]
+func (h *Handler) RestoreSongHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	song, err := h.repo.RestoreSong(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to restore song ID %s: %v", id, err)
+		if isNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song is not in the trash"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore song"})
+		return
+	}
+
+	c.JSON(http.StatusOK, song)
+}
+
+// @Summary Purge a song
+// @Description Permanently deletes a song in the trash, with its playlist entries, tracks and revisions. Admins only
+// @Tags trash
+// @Param id path string true "Song ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "song is not in the trash"
+// @Failure 500 {object} map[string]string "failed to purge song"
+// @Router /api/trash/{id} [delete]
+func (h *Handler) PurgeSongHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.PurgeSong(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to purge song ID %s: %v", id, err)
+		if isNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song is not in the trash"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to purge song"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "song purged"})
+}
+
 // Helper function to get integer query parameters with defaults
 func getIntQueryParam(c *gin.Context, key string, defaultValue int) int {
 	val, err := c.GetQuery(key)
@@ -246,16 +569,64 @@ func getIntQueryParam(c *gin.Context, key string, defaultValue int) int {
 	return intVal
 }
 
+// getPageRequest reads the keyset pagination parameters shared by song listings
+func getPageRequest(c *gin.Context) models.PageRequest {
+	return models.PageRequest{
+		Limit:  getIntQueryParam(c, "limit", 10),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+}
+
+// isNotFound reports whether err means the requested record does not exist
+func isNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// isForeignKeyViolation reports whether err means a referenced record does not exist
+func isForeignKeyViolation(err error) bool {
+	return errors.Is(err, gorm.ErrForeignKeyViolated)
+}
+
+// respondSongError maps song write errors to a status code
+func (h *Handler) respondSongError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrVersionMismatch):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, jsonpatch.ErrMalformed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, jsonpatch.ErrUnprocessable), errors.Is(err, models.ErrInvalidSong), errors.Is(err, models.ErrInvalidLRC):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "song or group not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// respondListError maps listing errors to a status code
+func (h *Handler) respondListError(c *gin.Context, err error) {
+	if errors.Is(err, cursor.ErrInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
+}
+
 // GetSongsByArtistHandler handles fetching songs by a specific artist
 // @Summary Get songs by artist
-// @Description Fetch songs by the given artist name with pagination
+// @Description Fetch songs by the given artist name with pagination. tag_facets counts the tags across all of the artist's songs
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param artist query string true "Artist name"
-// @Param limit query int false "Limit (default: 10)"
-// @Param offset query int false "Offset (default: 0)"
-// @Success 200 {array} models.Song
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param sort query string false "Sort key: created_at, release_date or name, prefixed with - for descending" default(created_at)
+// @Param cursor query string false "next_cursor or prev_cursor from a previous page"
+// @Success 200 {object} models.SongPage
+// @Success 304 "The client's copy is current"
 // @Failure 400 {object} map[string]string "Invalid request parameters"
 // @Failure 500 {object} map[string]string "Failed to fetch songs"
 // @Router /api/songs/artists [get]
@@ -274,19 +645,84 @@ func (h *Handler) GetSongsByArtistHandler(c *gin.Context) {
 		return
 	}
 
-	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	if err != nil {
-		h.logger.Printf("ERROR: Invalid offset parameter: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset parameter"})
+	page := getPageRequest(c)
+	page.Limit = limit
+	if h.listingNotModified(c, models.SongFilter{Artist: artist}) {
 		return
 	}
 
-	songs, err := h.repo.GetSongsByArtist(c, artist, limit, offset)
+	songs, err := h.repo.GetSongsByArtist(c, artist, page)
 	if err != nil {
 		h.logger.Printf("ERROR: Failed to get songs for artist %s: %v", artist, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch songs"})
+		h.respondListError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, songs)
 }
+
+// SearchSongsHandler handles full-text and fuzzy search over songs
+// @Summary Search songs
+// @Description In fulltext mode, ranks songs by matches in name, group and lyrics and highlights the best matching verse; web search syntax ("quoted phrases", -excluded, or) is supported.
+// @Description Each song is matched with the text search configuration of its detected language, so English and Russian words match their inflected forms.
+// @Description In fuzzy mode, ranks songs by trigram similarity of the query to the name, group or any artist, tolerating typos.
+// @Tags songs
+// @Produce json
+// @Param q query string true "Search query"
+// @Param mode query string false "Search mode" Enums(fulltext, fuzzy) default(fulltext)
+// @Param threshold query number false "Minimum similarity in fuzzy mode, between 0 and 1" default(0.3)
+// @Param language query string false "Only songs in this language (en, ru or uz)"
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.SearchResult
+// @Failure 400 {object} map[string]string "invalid search parameters"
+// @Failure 500 {object} map[string]string "failed to search songs"
+// @Router /api/search [get]
+func (h *Handler) SearchSongsHandler(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		h.logger.Println("ERROR: Search query is required")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "search query is required"})
+		return
+	}
+
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+	var language string
+	if val := c.Query("language"); val != "" {
+		var err error
+		if language, err = models.CanonicalLanguage(val); err != nil {
+			h.logger.Printf("ERROR: Invalid language parameter: %q", val)
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid language value %q", val)})
+			return
+		}
+	}
+
+	var (
+		results []models.SearchResult
+		err     error
+	)
+	switch mode := c.DefaultQuery("mode", "fulltext"); mode {
+	case "fulltext":
+		results, err = h.repo.SearchSongs(c, query, language, limit, offset)
+	case "fuzzy":
+		threshold, parseErr := strconv.ParseFloat(c.DefaultQuery("threshold", "0.3"), 64)
+		if parseErr != nil || threshold < 0 || threshold > 1 {
+			h.logger.Printf("ERROR: Invalid threshold parameter: %q", c.Query("threshold"))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a number between 0 and 1"})
+			return
+		}
+		results, err = h.repo.FuzzySearchSongs(c, query, language, threshold, limit, offset)
+	default:
+		h.logger.Printf("ERROR: Invalid search mode: %q", mode)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be fulltext or fuzzy"})
+		return
+	}
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to search songs for %q: %v", query, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search songs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/internal/repos"
+)
+
+// createRepo records the songs it is asked to create.
+type createRepo struct {
+	repos.Repo
+	created []models.Song
+}
+
+func (r *createRepo) CreateSong(_ context.Context, song *models.Song) error {
+	r.created = append(r.created, *song)
+	return nil
+}
+
+func postSong(h *Handler, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.POST("/api/songs", h.CreateSongHandler)
+	req := httptest.NewRequest(http.MethodPost, "/api/songs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestCreateSongIgnoresServerFields(t *testing.T) {
+	repo := &createRepo{}
+	h := &Handler{repo: repo, logger: log.New(io.Discard, "", 0)}
+
+	recorder := postSong(h, `{
+		"name": "Song", "group": "Band", "artists": ["One"], "link": "https://example.com/song",
+		"version": 42, "language": "ru",
+		"updated_at": "2020-01-01T00:00:00Z", "deleted_at": "2020-01-02T00:00:00Z", "is_deleted": true
+	}`)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d songs, want 1", len(repo.created))
+	}
+	song := repo.created[0]
+	if song.Name != "Song" || song.Group != "Band" || len(song.Artists) != 1 || song.Link != "https://example.com/song" {
+		t.Errorf("created %+v, want the editable fields from the request", song)
+	}
+	if song.Version != 0 || song.Language != "" || !song.UpdatedAt.IsZero() || song.DeletedAt != nil || song.IsDeleted {
+		t.Errorf("created %+v, want the version, language, timestamps and trash state left to the server", song)
+	}
+}
+
+func TestCreateSongValidates(t *testing.T) {
+	repo := &createRepo{}
+	h := &Handler{repo: repo, logger: log.New(io.Discard, "", 0)}
+
+	for _, body := range []string{
+		`{"name": " ", "artists": ["One"]}`,
+		`{"name": "Song", "artists": [""]}`,
+		`{"name": "Song", "link": "ftp://example.com"}`,
+	} {
+		if recorder := postSong(h, body); recorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status = %d, want %d", body, recorder.Code, http.StatusUnprocessableEntity)
+		}
+	}
+	if len(repo.created) != 0 {
+		t.Errorf("created %d invalid songs", len(repo.created))
+	}
+}
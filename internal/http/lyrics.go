@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/lrc"
+)
+
+// maxLRCSize bounds the size of an uploaded LRC file.
+const maxLRCSize = 1 << 20
+
+// @Summary Get a song's lyrics sections
+// @Description Parses the lyrics into typed sections (intro, verse, pre-chorus, chorus, hook, bridge, interlude, outro) with their lines. Labels such as "Chorus:" or "[Bridge]" set a section's type; unlabelled paragraphs are verses. A label with no lines repeats the last section of its type; with expand=true its lines are filled in
+// @Tags lyrics
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param type query string false "Only sections of this type"
+// @Param expand query bool false "Write out repeated sections" default(false)
+// @Success 200 {array} models.LyricsSection
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid section type"
+// @Failure 404 {object} map[string]string "song not found"
+// @Failure 500 {object} map[string]string "failed to fetch lyrics"
+// @Router /api/songs/{id}/lyrics/sections [get]
+func (h *Handler) GetSongLyricsSectionsHandler(c *gin.Context) {
+	id := c.Param("id")
+	sectionType := c.Query("type")
+	if sectionType != "" && !models.ValidSectionType(sectionType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid section type"})
+		return
+	}
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	sections, err := h.repo.GetSongLyricsSections(c, id, sectionType, c.Query("expand") == "true")
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch lyrics sections for song ID %s: %v", id, err)
+		h.respondLyricsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sections)
+}
+
+// @Summary Get a section of a song's lyrics
+// @Description Returns the section at the given position, counting from 0
+// @Tags lyrics
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param index path int true "Section index"
+// @Param expand query bool false "Write out the section if it repeats an earlier one" default(false)
+// @Success 200 {object} models.LyricsSection
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid section index"
+// @Failure 404 {object} map[string]string "song or section not found"
+// @Failure 500 {object} map[string]string "failed to fetch lyrics"
+// @Router /api/songs/{id}/lyrics/sections/{index} [get]
+func (h *Handler) GetSongLyricsSectionHandler(c *gin.Context) {
+	id := c.Param("id")
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid section index"})
+		return
+	}
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	section, err := h.repo.GetSongLyricsSection(c, id, index, c.Query("expand") == "true")
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch lyrics section %d for song ID %s: %v", index, id, err)
+		h.respondLyricsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, section)
+}
+
+// @Summary Upload a song's synced lyrics
+// @Description Stores LRC or enhanced LRC (word timings as <mm:ss.xx> tags) for the song. Lines must be in time order, except lines with several time tags, and word timings must be in order within their line. The song's plain lyrics are replaced by the text of the synced lyrics, recording a revision. Editing the plain lyrics later drops the synced version
+// @Tags lyrics
+// @Accept plain
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param lrc body string true "LRC file"
+// @Param If-Match header string false "ETag of the version being replaced; the write fails with 412 if the song has changed since"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Version of the song"
+// @Failure 400 {object} map[string]string "Invalid song ID or request body"
+// @Failure 404 {object} map[string]string "Song not found"
+// @Failure 412 {object} map[string]string "Song was changed since the given version"
+// @Failure 413 {object} map[string]string "LRC file too large"
+// @Failure 422 {object} map[string]string "Invalid LRC or timing out of order"
+// @Failure 500 {object} map[string]string "Failed to set synced lyrics"
+// @Router /api/songs/{id}/lyrics/lrc [put]
+func (h *Handler) SetSyncedLyricsHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid song ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid song ID"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxLRCSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "LRC file too large"})
+			return
+		}
+		h.logger.Printf("ERROR: Failed to read request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	song, err := h.repo.SetSyncedLyrics(c, id.String(), string(body), currentAuthor(c), ifMatch(c))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set synced lyrics for song ID %s: %v", id, err)
+		h.respondSongError(c, err, "failed to set synced lyrics")
+		return
+	}
+
+	c.Header("ETag", song.ETag())
+	c.JSON(http.StatusOK, song)
+}
+
+// @Summary Download a song's synced lyrics
+// @Description Returns the synced lyrics as an LRC file, with word timings unless words=false
+// @Tags lyrics
+// @Produce plain
+// @Param id path string true "Song ID"
+// @Param words query bool false "Include enhanced LRC word timings" default(true)
+// @Success 200 {string} string "LRC file"
+// @Failure 404 {object} map[string]string "song has no synced lyrics"
+// @Failure 500 {object} map[string]string "failed to fetch lyrics"
+// @Router /api/songs/{id}/lyrics/lrc [get]
+func (h *Handler) GetSyncedLyricsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	lyrics, err := h.repo.GetSyncedLyrics(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch synced lyrics for song ID %s: %v", id, err)
+		h.respondLyricsError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+id+`.lrc"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(lyrics.Format(c.Query("words") == "false")))
+}
+
+// @Summary Delete a song's synced lyrics
+// @Description Removes the synced lyrics; the song's plain lyrics stay as they are
+// @Tags lyrics
+// @Param id path string true "Song ID"
+// @Success 204
+// @Failure 404 {object} map[string]string "song has no synced lyrics"
+// @Failure 500 {object} map[string]string "failed to delete synced lyrics"
+// @Router /api/songs/{id}/lyrics/lrc [delete]
+func (h *Handler) DeleteSyncedLyricsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.DeleteSyncedLyrics(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete synced lyrics for song ID %s: %v", id, err)
+		if isNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete synced lyrics"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get the lyrics sung at a moment
+// @Description Finds the line and, for enhanced LRC, the word of the synced lyrics being sung t seconds into the song, along with the next line. line and word are null before the first line or the line's first word
+// @Tags lyrics
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param t query number true "Seconds from the start of the song"
+// @Success 200 {object} models.LyricsPosition
+// @Failure 400 {object} map[string]string "invalid time"
+// @Failure 404 {object} map[string]string "song has no synced lyrics"
+// @Failure 500 {object} map[string]string "failed to fetch lyrics"
+// @Router /api/songs/{id}/lyrics/at [get]
+func (h *Handler) GetLyricsAtHandler(c *gin.Context) {
+	id := c.Param("id")
+	seconds, err := strconv.ParseFloat(c.Query("t"), 64)
+	if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "t must be a number of seconds"})
+		return
+	}
+
+	position, err := h.repo.GetLyricsAt(c, id, lrc.Seconds(seconds))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch lyrics at %gs for song ID %s: %v", seconds, id, err)
+		h.respondLyricsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// respondLyricsError maps lyrics lookup errors to a status code
+func (h *Handler) respondLyricsError(c *gin.Context, err error) {
+	switch {
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch lyrics"})
+	}
+}
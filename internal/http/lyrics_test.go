@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetLyricsAtRejectsInvalidTimes(t *testing.T) {
+	// The repository is nil, so a time that got through would panic.
+	h := &Handler{logger: log.New(io.Discard, "", 0)}
+	router := gin.New()
+	router.GET("/api/songs/:id/lyrics/at", h.GetLyricsAtHandler)
+
+	for _, value := range []string{"", "soon", "-1", "NaN", "nan", "Inf", "+Inf", "-Inf", "infinity"} {
+		recorder := serveGet(router, "/api/songs/1/lyrics/at?t="+value, nil)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("t=%s: status = %d, want %d", value, recorder.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Create a playlist
+// @Description Creates a playlist owned by the signed-in user
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param playlist body models.Playlist true "Playlist object"
+// @Success 201 {object} models.Playlist
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/playlists [post]
+func (h *Handler) CreatePlaylistHandler(c *gin.Context) {
+	var playlist models.Playlist
+	if err := c.ShouldBindJSON(&playlist); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	playlist.ID = uuid.New()
+	playlist.OwnerID = currentUserID(c)
+
+	if err := h.repo.CreatePlaylist(c, &playlist); err != nil {
+		h.logger.Printf("ERROR: Failed to create playlist: %v", err)
+		h.respondPlaylistError(c, err, "failed to create playlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// @Summary Get playlists
+// @Description Fetches public playlists and the caller's own private ones, newest first
+// @Tags playlists
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.Playlist
+// @Failure 500 {object} map[string]string "failed to fetch playlists"
+// @Router /api/playlists [get]
+func (h *Handler) GetPlaylistsHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	playlists, err := h.repo.GetPlaylists(c, currentUserID(c), limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch playlists: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch playlists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, playlists)
+}
+
+// @Summary Get a playlist by ID
+// @Description Fetches a playlist with its entries in order. Entries whose song was deleted are hidden until the song is restored. Private playlists are only visible to their owner
+// @Tags playlists
+// @Produce json
+// @Param id path string true "Playlist ID"
+// @Success 200 {object} models.Playlist
+// @Failure 404 {object} map[string]string
+// @Router /api/playlists/{id} [get]
+func (h *Handler) GetPlaylistByIDHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	playlist, err := h.repo.GetPlaylistByID(c, id)
+	if err != nil || !canSeePlaylist(c, playlist) {
+		h.logger.Printf("ERROR: Failed to fetch playlist ID %s: %v", id, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary Update a playlist
+// @Description Updates a playlist's name, description and visibility
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Playlist ID"
+// @Param playlist body models.Playlist true "Playlist data"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 403 {object} map[string]string "not the playlist's owner"
+// @Failure 404 {object} map[string]string "playlist not found"
+// @Failure 500 {object} map[string]string "failed to update playlist"
+// @Router /api/playlists/{id} [put]
+func (h *Handler) UpdatePlaylistHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.logger.Printf("ERROR: Invalid playlist ID %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid playlist ID"})
+		return
+	}
+
+	var playlist models.Playlist
+	if err := c.ShouldBindJSON(&playlist); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	playlist.ID = id
+
+	current, ok := h.authorizePlaylist(c, id.String())
+	if !ok {
+		return
+	}
+	playlist.OwnerID = current.OwnerID
+
+	if err := h.repo.UpdatePlaylist(c, &playlist); err != nil {
+		h.logger.Printf("ERROR: Failed to update playlist ID %s: %v", id, err)
+		h.respondPlaylistError(c, err, "failed to update playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary Delete a playlist
+// @Description Deletes a playlist and all of its entries
+// @Tags playlists
+// @Param id path string true "Playlist ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string "not the playlist's owner"
+// @Failure 404 {object} map[string]string "playlist not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/playlists/{id} [delete]
+func (h *Handler) DeletePlaylistHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, ok := h.authorizePlaylist(c, id); !ok {
+		return
+	}
+
+	if err := h.repo.DeletePlaylist(c, id); err != nil {
+		h.logger.Printf("ERROR: Failed to delete playlist ID %s: %v", id, err)
+		h.respondPlaylistError(c, err, "failed to delete playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "playlist deleted"})
+}
+
+// @Summary Add a song to a playlist
+// @Description Adds a song right after or before another entry, or at the end when neither is given
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Playlist ID"
+// @Param entry body models.PlaylistEntryInput true "Song and position"
+// @Success 201 {object} models.PlaylistEntry
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 403 {object} map[string]string "not the playlist's owner"
+// @Failure 404 {object} map[string]string "playlist, song or neighbouring entry not found"
+// @Failure 500 {object} map[string]string "failed to add song"
+// @Router /api/playlists/{id}/entries [post]
+func (h *Handler) AddPlaylistEntryHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var input models.PlaylistEntryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if input.After != nil && input.Before != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "set either after or before, not both"})
+		return
+	}
+
+	if _, ok := h.authorizePlaylist(c, id); !ok {
+		return
+	}
+
+	entry, err := h.repo.AddPlaylistEntry(c, id, input)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to add song to playlist ID %s: %v", id, err)
+		h.respondPlaylistError(c, err, "failed to add song")
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary Move a playlist entry
+// @Description Moves an entry right after or before another entry, or to the end when neither is given. Other entries keep their positions
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Playlist ID"
+// @Param entry_id path string true "Entry ID"
+// @Param position body models.EntryPosition true "New position"
+// @Success 200 {object} models.PlaylistEntry
+// @Failure 400 {object} map[string]string "invalid request body"
+// @Failure 403 {object} map[string]string "not the playlist's owner"
+// @Failure 404 {object} map[string]string "playlist or entry not found"
+// @Failure 500 {object} map[string]string "failed to move entry"
+// @Router /api/playlists/{id}/entries/{entry_id}/move [post]
+func (h *Handler) MovePlaylistEntryHandler(c *gin.Context) {
+	id, entryID := c.Param("id"), c.Param("entry_id")
+
+	var position models.EntryPosition
+	if err := c.ShouldBindJSON(&position); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if position.After != nil && position.Before != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "set either after or before, not both"})
+		return
+	}
+	if entryUUID, err := uuid.Parse(entryID); err == nil && position.Beside(entryUUID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "an entry cannot be moved next to itself"})
+		return
+	}
+
+	if _, ok := h.authorizePlaylist(c, id); !ok {
+		return
+	}
+
+	entry, err := h.repo.MovePlaylistEntry(c, id, entryID, position)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to move entry ID %s of playlist ID %s: %v", entryID, id, err)
+		h.respondPlaylistError(c, err, "failed to move entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary Remove a playlist entry
+// @Tags playlists
+// @Param id path string true "Playlist ID"
+// @Param entry_id path string true "Entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string "not the playlist's owner"
+// @Failure 404 {object} map[string]string "entry not found"
+// @Failure 500 {object} map[string]string "failed to remove entry"
+// @Router /api/playlists/{id}/entries/{entry_id} [delete]
+func (h *Handler) RemovePlaylistEntryHandler(c *gin.Context) {
+	id, entryID := c.Param("id"), c.Param("entry_id")
+
+	if _, ok := h.authorizePlaylist(c, id); !ok {
+		return
+	}
+
+	if err := h.repo.RemovePlaylistEntry(c, id, entryID); err != nil {
+		h.logger.Printf("ERROR: Failed to remove entry ID %s from playlist ID %s: %v", entryID, id, err)
+		h.respondPlaylistError(c, err, "failed to remove entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "entry removed"})
+}
+
+// authorizePlaylist loads the playlist and lets only its owner or an admin
+// change it. Private playlists of other users are reported as not found.
+func (h *Handler) authorizePlaylist(c *gin.Context, id string) (*models.Playlist, bool) {
+	playlist, err := h.repo.GetPlaylistByID(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch playlist ID %s: %v", id, err)
+		h.respondPlaylistError(c, err, "failed to fetch playlist")
+		return nil, false
+	}
+	if !canSeePlaylist(c, playlist) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	if !canEditPlaylist(c, playlist) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can change this playlist"})
+		return nil, false
+	}
+	return playlist, true
+}
+
+func canSeePlaylist(c *gin.Context, playlist *models.Playlist) bool {
+	return playlist.IsPublic || canEditPlaylist(c, playlist)
+}
+
+func canEditPlaylist(c *gin.Context, playlist *models.Playlist) bool {
+	claims, ok := currentClaims(c)
+	if !ok {
+		return false
+	}
+	if models.RoleAtLeast(claims.Role, models.RoleAdmin) {
+		return true
+	}
+	userID := currentUserID(c)
+	return userID != nil && playlist.OwnerID != nil && *playlist.OwnerID == *userID
+}
+
+// respondPlaylistError maps playlist errors to a status code
+func (h *Handler) respondPlaylistError(c *gin.Context, err error, message string) {
+	switch {
+	case isNotFound(err), isForeignKeyViolation(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestMoveEntryNextToItself(t *testing.T) {
+	// The repository is nil, so a move that got through would panic.
+	h := &Handler{logger: log.New(io.Discard, "", 0)}
+	router := gin.New()
+	router.POST("/api/playlists/:id/entries/:entry_id/move", h.MovePlaylistEntryHandler)
+
+	entryID := uuid.New()
+	for _, path := range []string{entryID.String(), strings.ToUpper(entryID.String())} {
+		for _, body := range []string{`{"after": "` + entryID.String() + `"}`, `{"before": "` + entryID.String() + `"}`} {
+			req := httptest.NewRequest(http.MethodPost, "/api/playlists/"+uuid.NewString()+"/entries/"+path+"/move", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "itself") {
+				t.Errorf("entry %s, %s: %d %s, want 400 for moving next to itself", path, body, recorder.Code, recorder.Body)
+			}
+		}
+	}
+}
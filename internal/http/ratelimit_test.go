@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/internal/repos"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
+	"github.com/ruziba3vich/music_lib/pkg/ratelimit"
+)
+
+// apiKeyRepo rejects every API key and counts the lookups. The embedded
+// interface is nil, so any other repository call fails the test by panicking.
+type apiKeyRepo struct {
+	repos.Repo
+	lookups int
+}
+
+func (r *apiKeyRepo) AuthenticateAPIKey(context.Context, string) (*models.APIKey, error) {
+	r.lookups++
+	return nil, models.ErrInvalidAPIKey
+}
+
+func TestFailedAuthenticationIsThrottled(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	limiter := ratelimit.NewLimiter(client, map[string]ratelimit.Policy{
+		RateLimitAuth: {Limit: 2, Window: time.Minute},
+	})
+
+	repo := &apiKeyRepo{}
+	tokens := auth.NewIssuer([]byte("secret"), time.Minute, time.Hour)
+	h := NewHandler(repo, tokens, limiter, "", log.New(io.Discard, "", 0))
+	router := gin.New()
+	h.RegisterRoutes(router)
+
+	request := func(scheme, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/songs", nil)
+		req.Header.Set("Authorization", scheme)
+		req.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	failures := []string{"ApiKey guess-1", "Bearer not-a-jwt"}
+	for _, scheme := range failures {
+		if code := request(scheme, "10.0.0.1:1234"); code != http.StatusUnauthorized {
+			t.Fatalf("%s: status = %d, want %d", scheme, code, http.StatusUnauthorized)
+		}
+	}
+	if code := request("ApiKey guess-2", "10.0.0.1:1234"); code != http.StatusTooManyRequests {
+		t.Fatalf("status after %d failures = %d, want %d", len(failures), code, http.StatusTooManyRequests)
+	}
+	if repo.lookups != 1 {
+		t.Errorf("API key lookups = %d, want 1; throttled guesses must not reach the database", repo.lookups)
+	}
+
+	// Other addresses keep their own allowance.
+	if code := request("ApiKey guess-3", "10.0.0.2:1234"); code != http.StatusUnauthorized {
+		t.Errorf("status from another address = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
+)
+
+// @Summary Get a song's revisions
+// @Description Lists the revisions of a song, newest first. Each revision holds the song's state before an update, with who made the update and when
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.SongRevision
+// @Success 304 "The client's copy is current"
+// @Failure 500 {object} map[string]string "failed to fetch revisions"
+// @Router /api/songs/{id}/revisions [get]
+func (h *Handler) GetSongRevisionsHandler(c *gin.Context) {
+	id := c.Param("id")
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	revisions, err := h.repo.GetSongRevisions(c, id, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch revisions of song ID %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// @Summary Get a song revision
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.SongRevision
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid revision number"
+// @Failure 404 {object} map[string]string "revision not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/songs/{id}/revisions/{number} [get]
+func (h *Handler) GetSongRevisionHandler(c *gin.Context) {
+	id := c.Param("id")
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision number"})
+		return
+	}
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	revision, err := h.repo.GetSongRevision(c, id, number)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch revision %d of song ID %s: %v", number, id, err)
+		h.respondRevisionError(c, err, "failed to fetch revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// @Summary Compare two song revisions
+// @Description Lists the fields that changed between two revisions, with a line-by-line diff of the lyrics. Without to, the revision is compared with the song as it is now
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param from query int true "Revision to compare from"
+// @Param to query int false "Revision to compare to; defaults to the current song"
+// @Success 200 {object} models.SongDiff
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} map[string]string "invalid revision number"
+// @Failure 404 {object} map[string]string "song or revision not found"
+// @Failure 500 {object} map[string]string
+// @Router /api/songs/{id}/revisions/diff [get]
+func (h *Handler) DiffSongRevisionsHandler(c *gin.Context) {
+	id := c.Param("id")
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision number"})
+		return
+	}
+	var to *int
+	if value, ok := c.GetQuery("to"); ok {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision number"})
+			return
+		}
+		to = &number
+	}
+
+	if h.songNotModified(c, id) {
+		return
+	}
+
+	diff, err := h.repo.DiffSongRevisions(c, id, from, to)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to compare revisions of song ID %s: %v", id, err)
+		h.respondRevisionError(c, err, "failed to compare revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Summary Roll a song back to a revision
+// @Description Restores the song to the state saved in a revision. The state being replaced is kept as a new revision, so a rollback can itself be undone
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.Song
+// @Failure 400 {object} map[string]string "invalid revision number"
+// @Failure 404 {object} map[string]string "song or revision not found"
+// @Failure 500 {object} map[string]string "failed to roll back song"
+// @Router /api/songs/{id}/revisions/{number}/rollback [post]
+func (h *Handler) RollbackSongHandler(c *gin.Context) {
+	id := c.Param("id")
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision number"})
+		return
+	}
+
+	song, err := h.repo.RollbackSong(c, id, number, currentAuthor(c))
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to roll song ID %s back to revision %d: %v", id, number, err)
+		h.respondRevisionError(c, err, "failed to roll back song")
+		return
+	}
+
+	c.JSON(http.StatusOK, song)
+}
+
+// respondRevisionError maps revision errors to a status code
+func (h *Handler) respondRevisionError(c *gin.Context, err error, message string) {
+	switch {
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// currentAuthor returns the user or API key making the request, for revision history.
+func currentAuthor(c *gin.Context) models.Author {
+	claims, ok := currentClaims(c)
+	if !ok {
+		return models.Author{}
+	}
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return models.Author{}
+	}
+	if claims.Kind == auth.KindAPIKey {
+		return models.Author{APIKeyID: &id}
+	}
+	return models.Author{UserID: &id}
+}
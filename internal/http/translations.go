@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"golang.org/x/text/language"
+)
+
+// @Summary List a song's translations
+// @Description Lists the languages the song's lyrics are translated into. A translation is outdated when the lyrics changed after it was written
+// @Tags translations
+// @Produce json
+// @Param id path string true "Song ID"
+// @Success 200 {array} models.SongTranslation
+// @Failure 404 {object} map[string]string "song not found"
+// @Failure 500 {object} map[string]string "failed to fetch translations"
+// @Router /api/songs/{id}/translations [get]
+func (h *Handler) GetSongTranslationsHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	translations, err := h.repo.GetSongTranslations(c, id)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch translations for song ID %s: %v", id, err)
+		h.respondTranslationError(c, err, "failed to fetch translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// @Summary Get a song's translation
+// @Description Returns the translation into a language, one line per line of the lyrics
+// @Tags translations
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param language path string true "BCP 47 language tag"
+// @Success 200 {object} models.SongTranslation
+// @Failure 400 {object} map[string]string "invalid language tag"
+// @Failure 404 {object} map[string]string "song or translation not found"
+// @Failure 500 {object} map[string]string "failed to fetch translation"
+// @Router /api/songs/{id}/translations/{language} [get]
+func (h *Handler) GetSongTranslationHandler(c *gin.Context) {
+	id := c.Param("id")
+	lang, err := models.CanonicalLanguage(c.Param("language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation, err := h.repo.GetSongTranslation(c, id, lang)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch %s translation for song ID %s: %v", lang, id, err)
+		h.respondTranslationError(c, err, "failed to fetch translation")
+		return
+	}
+
+	c.Header("Content-Language", lang)
+	c.JSON(http.StatusOK, translation)
+}
+
+// @Summary Set a song's translation
+// @Description Stores the lyrics translated into a language. Send either lines, one per line of the lyrics (without section labels or blank lines), or text laid out like the lyrics. The number of lines must match the lyrics
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param language path string true "BCP 47 language tag"
+// @Param translation body models.TranslationInput true "Translation"
+// @Success 200 {object} models.SongTranslation
+// @Failure 400 {object} map[string]string "invalid language tag or request body"
+// @Failure 404 {object} map[string]string "song not found"
+// @Failure 422 {object} map[string]string "translation does not line up with the lyrics"
+// @Failure 500 {object} map[string]string "failed to set translation"
+// @Router /api/songs/{id}/translations/{language} [put]
+func (h *Handler) SetSongTranslationHandler(c *gin.Context) {
+	id := c.Param("id")
+	lang, err := models.CanonicalLanguage(c.Param("language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input models.TranslationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	translation, err := h.repo.SetSongTranslation(c, id, lang, input.TranslationLines())
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set %s translation for song ID %s: %v", lang, id, err)
+		h.respondTranslationError(c, err, "failed to set translation")
+		return
+	}
+
+	c.Header("Content-Language", lang)
+	c.JSON(http.StatusOK, translation)
+}
+
+// @Summary Delete a song's translation
+// @Tags translations
+// @Param id path string true "Song ID"
+// @Param language path string true "BCP 47 language tag"
+// @Success 204
+// @Failure 400 {object} map[string]string "invalid language tag"
+// @Failure 404 {object} map[string]string "translation not found"
+// @Failure 500 {object} map[string]string "failed to delete translation"
+// @Router /api/songs/{id}/translations/{language} [delete]
+func (h *Handler) DeleteSongTranslationHandler(c *gin.Context) {
+	id := c.Param("id")
+	lang, err := models.CanonicalLanguage(c.Param("language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteSongTranslation(c, id, lang); err != nil {
+		h.logger.Printf("ERROR: Failed to delete %s translation for song ID %s: %v", lang, id, err)
+		h.respondTranslationError(c, err, "failed to delete translation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get song lyrics side by side with a translation
+// @Description Pages through the lyrics like /api/songs/{id}/lyrics, pairing each section with its translation. The language is taken from lang or else negotiated from Accept-Language among the song's translations, and returned in Content-Language
+// @Tags translations
+// @Produce json
+// @Param id path string true "Song ID"
+// @Param lang query string false "BCP 47 language tag; overrides Accept-Language"
+// @Param Accept-Language header string false "Preferred languages"
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.TranslatedVerse
+// @Header 200 {string} Content-Language "Language of the translation"
+// @Failure 400 {object} map[string]string "invalid language tag"
+// @Failure 404 {object} map[string]string "song or translation not found"
+// @Failure 406 {object} map[string]any "no translation in an acceptable language"
+// @Failure 500 {object} map[string]string "failed to fetch lyrics"
+// @Router /api/songs/{id}/lyrics/translated [get]
+func (h *Handler) GetTranslatedLyricsPaginatedHandler(c *gin.Context) {
+	id := c.Param("id")
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	lang := c.Query("lang")
+	if lang != "" {
+		canonical, err := models.CanonicalLanguage(lang)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		lang = canonical
+	} else {
+		c.Header("Vary", "Accept-Language")
+		translations, err := h.repo.GetSongTranslations(c, id)
+		if err != nil {
+			h.logger.Printf("ERROR: Failed to fetch translations for song ID %s: %v", id, err)
+			h.respondTranslationError(c, err, "failed to fetch lyrics")
+			return
+		}
+		available := make([]string, 0, len(translations))
+		for _, translation := range translations {
+			available = append(available, translation.Language)
+		}
+		if len(available) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "song has no translations"})
+			return
+		}
+		var ok bool
+		if lang, ok = negotiateLanguage(c.GetHeader("Accept-Language"), available); !ok {
+			c.JSON(http.StatusNotAcceptable, gin.H{"error": "no translation in an acceptable language", "available": available})
+			return
+		}
+	}
+
+	verses, err := h.repo.GetTranslatedLyricsPaginated(c, id, lang, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch lyrics for song ID %s with %s translation: %v", id, lang, err)
+		h.respondTranslationError(c, err, "failed to fetch lyrics")
+		return
+	}
+
+	c.Header("Content-Language", lang)
+	c.JSON(http.StatusOK, verses)
+}
+
+// negotiateLanguage picks the available language that best matches an
+// Accept-Language header. Without a header, any language is acceptable and
+// the first is chosen.
+func negotiateLanguage(header string, available []string) (string, bool) {
+	if header == "" {
+		return available[0], true
+	}
+	preferred, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(preferred) == 0 {
+		return "", false
+	}
+	supported := make([]language.Tag, 0, len(available))
+	for _, lang := range available {
+		supported = append(supported, language.Make(lang))
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence != language.No {
+		return available[index], true
+	}
+	for _, tag := range preferred {
+		// "*" accepts any language; it parses as "mul".
+		if tag == language.Make("mul") {
+			return available[0], true
+		}
+	}
+	return "", false
+}
+
+// respondTranslationError maps translation errors to a status code
+func (h *Handler) respondTranslationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrMisaligned):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case isNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
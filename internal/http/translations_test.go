@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/internal/repos"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	available := []string{"en", "ru", "uz-Latn"}
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{header: "", want: "en", ok: true},
+		{header: "ru", want: "ru", ok: true},
+		{header: "ru-RU,ru;q=0.9", want: "ru", ok: true},
+		{header: "de;q=1.0, ru;q=0.5, en;q=0.8", want: "en", ok: true},
+		{header: "en;q=0.2, ru;q=0.9", want: "ru", ok: true},
+		{header: "uz", want: "uz-Latn", ok: true},
+		{header: "de, *;q=0.1", want: "en", ok: true},
+		{header: "*", want: "en", ok: true},
+		{header: "de, fr", ok: false},
+		{header: "ru;q=0", ok: false},
+		{header: "not a language;;", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := negotiateLanguage(tt.header, available)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("negotiateLanguage(%q) = %q, %t, want %q, %t", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// translationRepo serves the translated lyrics of one song and records the
+// language they were asked in. Other repository calls panic.
+type translationRepo struct {
+	repos.Repo
+	languages []string
+	requested []string
+}
+
+func (r *translationRepo) GetSongTranslations(context.Context, string) ([]models.SongTranslation, error) {
+	translations := []models.SongTranslation{}
+	for _, lang := range r.languages {
+		translations = append(translations, models.SongTranslation{Language: lang})
+	}
+	return translations, nil
+}
+
+func (r *translationRepo) GetTranslatedLyricsPaginated(_ context.Context, _, language string, _, _ int) ([]models.TranslatedVerse, error) {
+	r.requested = append(r.requested, language)
+	return []models.TranslatedVerse{{Original: "la", Translation: language}}, nil
+}
+
+func TestTranslatedLyricsLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		accept   string
+		status   int
+		language string
+	}{
+		{name: "negotiated", target: "", accept: "de, ru;q=0.5", status: http.StatusOK, language: "ru"},
+		{name: "lang overrides header", target: "?lang=EN", accept: "ru", status: http.StatusOK, language: "en"},
+		{name: "lang not checked against header", target: "?lang=fr", accept: "ru", status: http.StatusOK, language: "fr"},
+		{name: "invalid lang", target: "?lang=-", accept: "ru", status: http.StatusBadRequest},
+		{name: "nothing acceptable", target: "", accept: "de", status: http.StatusNotAcceptable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &translationRepo{languages: []string{"en", "ru"}}
+			h := &Handler{repo: repo, logger: log.New(io.Discard, "", 0)}
+			router := gin.New()
+			router.GET("/api/songs/:id/lyrics/translated", h.GetTranslatedLyricsPaginatedHandler)
+
+			recorder := serveGet(router, "/api/songs/1/lyrics/translated"+tt.target, http.Header{"Accept-Language": {tt.accept}})
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			if tt.status != http.StatusOK {
+				if len(repo.requested) != 0 {
+					t.Errorf("lyrics fetched in %v", repo.requested)
+				}
+				return
+			}
+			if got := recorder.Header().Get("Content-Language"); got != tt.language {
+				t.Errorf("Content-Language = %q, want %q", got, tt.language)
+			}
+			if len(repo.requested) != 1 || repo.requested[0] != tt.language {
+				t.Errorf("lyrics fetched in %v, want %s", repo.requested, tt.language)
+			}
+		})
+	}
+}
+
+func TestTranslatedLyricsNotAcceptableListsLanguages(t *testing.T) {
+	h := &Handler{repo: &translationRepo{languages: []string{"en", "ru"}}, logger: log.New(io.Discard, "", 0)}
+	router := gin.New()
+	router.GET("/api/songs/:id/lyrics/translated", h.GetTranslatedLyricsPaginatedHandler)
+
+	recorder := serveGet(router, "/api/songs/1/lyrics/translated", http.Header{"Accept-Language": {"de"}})
+	var body struct {
+		Available []string `json:"available"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Available) != 2 || body.Available[0] != "en" || body.Available[1] != "ru" {
+		t.Errorf("available = %v, want [en ru]", body.Available)
+	}
+	if vary := recorder.Header().Get("Vary"); vary != "Accept-Language" {
+		t.Errorf("Vary = %q, want Accept-Language", vary)
+	}
+
+	h.repo = &translationRepo{}
+	if recorder := serveGet(router, "/api/songs/1/lyrics/translated", nil); recorder.Code != http.StatusNotFound {
+		t.Errorf("song without translations: status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// @Summary Get all users
+// @Description Fetches accounts ordered by username. Admins only
+// @Tags users
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} models.User
+// @Failure 401 {object} map[string]string "authentication required"
+// @Failure 403 {object} map[string]string "requires the admin role"
+// @Failure 500 {object} map[string]string "failed to fetch users"
+// @Router /api/users [get]
+func (h *Handler) GetUsersHandler(c *gin.Context) {
+	limit := getIntQueryParam(c, "limit", 10)
+	offset := getIntQueryParam(c, "offset", 0)
+
+	users, err := h.repo.GetUsers(c, limit, offset)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to fetch users: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Set a user's role
+// @Description Changes a user's role to viewer, editor or admin. Admins only. The new role applies from the user's next token refresh
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role body models.RoleUpdate true "New role"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string "invalid request body or role"
+// @Failure 401 {object} map[string]string "authentication required"
+// @Failure 403 {object} map[string]string "requires the admin role"
+// @Failure 404 {object} map[string]string "user not found"
+// @Failure 500 {object} map[string]string "failed to set role"
+// @Router /api/users/{id}/role [put]
+func (h *Handler) SetUserRoleHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var update models.RoleUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		h.logger.Printf("ERROR: Failed to parse request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if !models.ValidUserRole(update.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown role " + update.Role})
+		return
+	}
+
+	user, err := h.repo.SetUserRole(c, id, update.Role)
+	if err != nil {
+		h.logger.Printf("ERROR: Failed to set role of user ID %s: %v", id, err)
+		h.respondUserError(c, err, "failed to set role")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Album is a body of work that can be published as one or more releases,
+// e.g. the original pressing and a deluxe edition.
+type Album struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Title     string     `gorm:"not null" json:"title" binding:"required"`
+	GroupID   *uuid.UUID `gorm:"type:uuid;index" json:"group_id"`
+	Releases  []Release  `gorm:"foreignKey:AlbumID;constraint:OnDelete:SET NULL" json:"releases,omitempty" binding:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Release is a concrete edition with its own label, date and tracklist.
+type Release struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	AlbumID     *uuid.UUID `gorm:"type:uuid;index" json:"album_id"`
+	Title       string     `gorm:"not null" json:"title" binding:"required"`
+	ReleaseDate *time.Time `gorm:"type:date" json:"release_date"`
+	Label       string     `json:"label"`
+	Format      string     `json:"format"`
+	Tracks      []Track    `gorm:"foreignKey:ReleaseID;constraint:OnDelete:CASCADE" json:"tracks,omitempty" binding:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Track places a song on a release. Track numbers start at 1 on every disc.
+type Track struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ReleaseID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tracks_position" json:"release_id"`
+	DiscNumber  int       `gorm:"not null;uniqueIndex:idx_tracks_position" json:"disc_number"`
+	TrackNumber int       `gorm:"not null;uniqueIndex:idx_tracks_position" json:"track_number"`
+	SongID      uuid.UUID `gorm:"type:uuid;not null;index" json:"song_id"`
+	Song        *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"song,omitempty"`
+}
+
+// TrackInput places a song on a release. Zero disc or track numbers mean
+// "disc 1" and "at the end of the disc".
+type TrackInput struct {
+	SongID      uuid.UUID `json:"song_id" binding:"required"`
+	DiscNumber  int       `json:"disc_number" binding:"gte=0"`
+	TrackNumber int       `json:"track_number" binding:"gte=0"`
+}
+
+// TrackPosition is where a track is moved to within its release.
+type TrackPosition struct {
+	DiscNumber  int `json:"disc_number" binding:"gte=0"`
+	TrackNumber int `json:"track_number" binding:"gte=0"`
+}
+
+// SongAppearance is a release a song appears on and its place in the tracklist.
+type SongAppearance struct {
+	Release     Release `json:"release"`
+	DiscNumber  int     `json:"disc_number"`
+	TrackNumber int     `json:"track_number"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// API key scopes. Each scope grants what the matching user role does:
+// songs:read a viewer's access, songs:write an editor's, admin an admin's.
+const (
+	ScopeSongsRead  = "songs:read"
+	ScopeSongsWrite = "songs:write"
+	ScopeAdmin      = "admin"
+)
+
+var scopeRoles = map[string]string{
+	ScopeSongsRead:  RoleViewer,
+	ScopeSongsWrite: RoleEditor,
+	ScopeAdmin:      RoleAdmin,
+}
+
+// ValidAPIKeyScope reports whether scope is one of the known API key scopes.
+func ValidAPIKeyScope(scope string) bool {
+	_, ok := scopeRoles[scope]
+	return ok
+}
+
+// APIKey lets a machine client call the API without a user login. Only a
+// hash of the key is stored; the key itself is shown once, on creation.
+type APIKey struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	Name       string         `gorm:"not null" json:"name"`
+	Prefix     string         `gorm:"not null" json:"prefix"`
+	KeyHash    string         `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes     pq.StringArray `gorm:"type:text[];not null" json:"scopes"`
+	CreatedBy  *uuid.UUID     `gorm:"type:uuid;index" json:"created_by"`
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// Role returns the most privileged role the key's scopes grant.
+func (k *APIKey) Role() string {
+	role := ""
+	for _, scope := range k.Scopes {
+		if granted, ok := scopeRoles[scope]; ok && (role == "" || RoleAtLeast(granted, role)) {
+			role = granted
+		}
+	}
+	return role
+}
+
+// Active reports whether the key can still be used at now.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyInput describes a key to create.
+type APIKeyInput struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// NewAPIKey is a freshly created key together with its secret value.
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
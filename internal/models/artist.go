@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles an artist can be credited with on a song.
+const (
+	RolePerformer = "performer"
+	RoleFeatured  = "featured"
+	RoleComposer  = "composer"
+	RoleLyricist  = "lyricist"
+	RoleProducer  = "producer"
+)
+
+var artistRoles = map[string]bool{
+	RolePerformer: true,
+	RoleFeatured:  true,
+	RoleComposer:  true,
+	RoleLyricist:  true,
+	RoleProducer:  true,
+}
+
+// ValidArtistRole reports whether role is a known credit role.
+func ValidArtistRole(role string) bool {
+	return artistRoles[role]
+}
+
+type Artist struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name           string    `gorm:"not null" json:"name" binding:"required"`
+	NormalizedName string    `gorm:"not null;uniqueIndex" json:"-"`
+	Country        string    `json:"country"`
+	Bio            string    `gorm:"type:text" json:"bio"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SongArtist credits an artist on a song. Position orders the credits as they
+// appear in Song.Artists.
+type SongArtist struct {
+	SongID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	ArtistID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"artist_id"`
+	Position int       `gorm:"not null" json:"position"`
+	Role     string    `gorm:"not null;default:performer" json:"role"`
+	Artist   Artist    `gorm:"foreignKey:ArtistID;constraint:OnDelete:RESTRICT" json:"artist"`
+}
+
+// SongArtistInput is one entry of the credit list accepted by PUT /api/songs/:id/artists.
+type SongArtistInput struct {
+	ArtistID uuid.UUID `json:"artist_id" binding:"required"`
+	Role     string    `json:"role"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// Formats accepted by bulk import and export.
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// ValidBulkFormat reports whether format is a supported bulk format.
+func ValidBulkFormat(format string) bool {
+	return format == FormatCSV || format == FormatJSON || format == FormatNDJSON
+}
+
+// ImportOptions controls a bulk import.
+type ImportOptions struct {
+	Format    string `json:"format"`
+	DryRun    bool   `json:"dry_run"`
+	BatchSize int    `json:"batch_size"`
+}
+
+// ImportError is a row that could not be imported. Rows are numbered from 1,
+// not counting a CSV header.
+type ImportError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarises a bulk import. In a dry run, Imported counts the rows
+// that would have been imported.
+type ImportReport struct {
+	DryRun   bool          `json:"dry_run"`
+	Rows     int           `json:"rows"`
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Errors   []ImportError `json:"errors"`
+}
+
+// ExportOptions controls a bulk export. Only songs matching Filter are exported.
+type ExportOptions struct {
+	Format     string     `json:"format"`
+	Filter     SongFilter `json:"filter"`
+	OmitLyrics bool       `json:"omit_lyrics"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "errors"
+
+var (
+	ErrArtistInUse      = errors.New("artist is credited on songs")
+	ErrDuplicateArtist  = errors.New("artist with this name already exists")
+	ErrGroupInUse       = errors.New("group is referenced by songs")
+	ErrDuplicateGroup   = errors.New("group with this name already exists")
+	ErrDuplicateGenre   = errors.New("genre with this slug already exists")
+	ErrGenreHasChildren = errors.New("genre has subgenres")
+	ErrGenreCycle       = errors.New("genre cannot be its own ancestor")
+	ErrDuplicateUser    = errors.New("username is already taken")
+	ErrBadCredentials   = errors.New("invalid username or password")
+	ErrInvalidUsername  = errors.New("username must be 3 to 64 characters long")
+	ErrInvalidPassword  = errors.New("password must be 8 to 72 characters long")
+	ErrInvalidAPIKey    = errors.New("invalid, expired or revoked API key")
+	ErrInvalidSong      = errors.New("invalid song")
+	ErrVersionMismatch  = errors.New("song was changed since the given version")
+	ErrInvalidLRC       = errors.New("invalid LRC")
+	ErrInvalidLanguage  = errors.New("invalid BCP 47 language tag")
+	ErrMisaligned       = errors.New("translation does not line up with the lyrics")
+)
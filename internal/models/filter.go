@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SongFilter describes the criteria accepted by GET /api/songs/filtered.
+// Zero values mean "no constraint", except Deleted which defaults to live songs only.
+type SongFilter struct {
+	Name         string     `json:"name,omitempty"`
+	Group        string     `json:"group,omitempty"`
+	GroupID      *uuid.UUID `json:"group_id,omitempty"`
+	Artist       string     `json:"artist,omitempty"`
+	Genre        string     `json:"genre,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Language     string     `json:"language,omitempty"`
+	ReleasedFrom *time.Time `json:"released_from,omitempty"`
+	ReleasedTo   *time.Time `json:"released_to,omitempty"`
+	CreatedFrom  *time.Time `json:"created_from,omitempty"`
+	CreatedTo    *time.Time `json:"created_to,omitempty"`
+	Deleted      bool       `json:"deleted,omitempty"`
+}
+
+// FilterParams are the parameters ParseSongFilter reads into a SongFilter,
+// apart from deleted, which only some callers accept.
+var FilterParams = []string{
+	"name", "group", "group_id", "artist", "genre", "tag", "language",
+	"released_from", "released_to", "created_from", "created_to",
+}
+
+// ParseSongFilter builds a SongFilter from query parameters, rejecting
+// parameters that are not in allowed. Dates are YYYY-MM-DD or RFC 3339; a
+// date given as an upper bound covers the whole day.
+func ParseSongFilter(values url.Values, allowed map[string]bool) (SongFilter, error) {
+	var filter SongFilter
+
+	for key := range values {
+		if !allowed[key] {
+			return filter, fmt.Errorf("unknown filter %q", key)
+		}
+	}
+
+	filter.Name = values.Get("name")
+	filter.Group = values.Get("group")
+	filter.Artist = values.Get("artist")
+	filter.Genre = values.Get("genre")
+	filter.Tags = values["tag"]
+
+	if val := values.Get("language"); val != "" {
+		language, err := CanonicalLanguage(val)
+		if err != nil {
+			return filter, fmt.Errorf("invalid language value %q", val)
+		}
+		filter.Language = language
+	}
+
+	if val := values.Get("group_id"); val != "" {
+		groupID, err := uuid.Parse(val)
+		if err != nil {
+			return filter, fmt.Errorf("invalid group_id value %q", val)
+		}
+		filter.GroupID = &groupID
+	}
+
+	var err error
+	if filter.ReleasedFrom, err = ParseFilterTime(values, "released_from"); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedTo, err = parseFilterUpperBound(values, "released_to"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = ParseFilterTime(values, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseFilterUpperBound(values, "created_to"); err != nil {
+		return filter, err
+	}
+
+	if val, ok := values["deleted"]; ok {
+		deleted, err := strconv.ParseBool(val[0])
+		if err != nil {
+			return filter, fmt.Errorf("invalid deleted value %q", val[0])
+		}
+		filter.Deleted = deleted
+	}
+
+	if filter.ReleasedFrom != nil && filter.ReleasedTo != nil && filter.ReleasedFrom.After(*filter.ReleasedTo) {
+		return filter, fmt.Errorf("released_from must not be after released_to")
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedFrom.After(*filter.CreatedTo) {
+		return filter, fmt.Errorf("created_from must not be after created_to")
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime accepts either a date (2006-01-02) or an RFC 3339 timestamp.
+func ParseFilterTime(values url.Values, key string) (*time.Time, error) {
+	val := values.Get(key)
+	if val == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, val); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s value %q, expected YYYY-MM-DD or RFC 3339", key, val)
+}
+
+// parseFilterUpperBound reads an inclusive upper bound like parseFilterTime,
+// except that a date covers the whole day: it becomes the last microsecond of
+// the day, the finest time PostgreSQL stores.
+func parseFilterUpperBound(values url.Values, key string) (*time.Time, error) {
+	t, err := ParseFilterTime(values, key)
+	if t == nil || err != nil {
+		return t, err
+	}
+	if _, dateErr := time.Parse(time.DateOnly, values.Get(key)); dateErr == nil {
+		end := t.AddDate(0, 0, 1).Add(-time.Microsecond)
+		return &end, nil
+	}
+	return t, nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Genre is a node in the genre taxonomy, e.g. post-punk under rock.
+type Genre struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name      string     `gorm:"not null" json:"name" binding:"required"`
+	Slug      string     `gorm:"not null;uniqueIndex" json:"slug"`
+	ParentID  *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
+	Parent    *Genre     `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT" json:"-" binding:"-"`
+	Children  []*Genre   `gorm:"-" json:"children,omitempty" binding:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SongGenre classifies a song under a genre.
+type SongGenre struct {
+	SongID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	GenreID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Genre   Genre     `gorm:"foreignKey:GenreID;constraint:OnDelete:CASCADE"`
+}
+
+// Tag is a free-form label. Names are stored normalized.
+type Tag struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name string    `gorm:"not null;uniqueIndex" json:"name"`
+}
+
+// SongTag attaches a tag to a song.
+type SongTag struct {
+	SongID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID  uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Tag    Tag       `gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE"`
+}
+
+// TagFacet is the number of songs in a listing that carry a tag.
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// GenreSlug derives a URL-friendly identifier from a genre name, e.g. "Post Punk" -> "post-punk".
+func GenreSlug(name string) string {
+	return strings.ReplaceAll(NormalizeName(name), " ", "-")
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Group struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name           string     `gorm:"not null" json:"name" binding:"required"`
+	NormalizedName string     `gorm:"not null;uniqueIndex" json:"-"`
+	FormedOn       *time.Time `gorm:"type:date" json:"formed_on"`
+	DissolvedOn    *time.Time `gorm:"type:date" json:"dissolved_on"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// GroupMember is one stint of an artist in a group. An artist who left and
+// rejoined has one membership per stint. A nil JoinedOn means "since the group
+// formed" and a nil LeftOn means the artist is still a member.
+type GroupMember struct {
+	ID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	GroupID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"group_id"`
+	ArtistID uuid.UUID  `gorm:"type:uuid;not null;index" json:"artist_id" binding:"required"`
+	Role     string     `json:"role"`
+	JoinedOn *time.Time `gorm:"type:date" json:"joined_on"`
+	LeftOn   *time.Time `gorm:"type:date" json:"left_on"`
+	Artist   Artist     `gorm:"foreignKey:ArtistID;constraint:OnDelete:RESTRICT" json:"artist" binding:"-"`
+	Group    Group      `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-" binding:"-"`
+}
+
+// GroupCatalogue is a group's line-up on a given date together with a page of
+// the songs it had released by then.
+type GroupCatalogue struct {
+	Group  Group         `json:"group"`
+	AsOf   time.Time     `json:"as_of"`
+	LineUp []GroupMember `json:"line_up"`
+	SongPage
+}
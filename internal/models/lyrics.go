@@ -0,0 +1,243 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/pkg/lrc"
+)
+
+// Lyrics section types.
+const (
+	SectionIntro     = "intro"
+	SectionVerse     = "verse"
+	SectionPreChorus = "pre-chorus"
+	SectionChorus    = "chorus"
+	SectionHook      = "hook"
+	SectionBridge    = "bridge"
+	SectionInterlude = "interlude"
+	SectionOutro     = "outro"
+)
+
+// sectionTypes maps the words used in section labels to section types.
+var sectionTypes = map[string]string{
+	"intro":      SectionIntro,
+	"verse":      SectionVerse,
+	"pre-chorus": SectionPreChorus,
+	"prechorus":  SectionPreChorus,
+	"chorus":     SectionChorus,
+	"refrain":    SectionChorus,
+	"hook":       SectionHook,
+	"bridge":     SectionBridge,
+	"interlude":  SectionInterlude,
+	"outro":      SectionOutro,
+}
+
+// sectionLabel matches a label such as "Verse 2", "Chorus x2" or "Pre-Chorus".
+var sectionLabel = regexp.MustCompile(`(?i)^(intro|verse|pre-?chorus|chorus|refrain|hook|bridge|interlude|outro)(?:\s+(\d+))?(?:\s*[x×]\s*(\d+))?$`)
+
+// repeatMarker matches a repeat count written after a label's colon, as in "Chorus: x2".
+var repeatMarker = regexp.MustCompile(`(?i)^[x×]\s*\d+$`)
+
+// ValidSectionType reports whether t is one of the section types.
+func ValidSectionType(t string) bool {
+	return sectionTypes[t] == t && t != ""
+}
+
+// LyricsSection is a labelled part of a song's lyrics. A section that is only a
+// label, such as a second "Chorus:" with no lines, repeats the last section of
+// that type; RepeatOf is that section's index and its lines are filled in
+// when the lyrics are expanded.
+type LyricsSection struct {
+	Index    int      `json:"index"`
+	Type     string   `json:"type"`
+	Number   int      `json:"number"`
+	Label    string   `json:"label,omitempty"`
+	Lines    []string `json:"lines"`
+	RepeatOf *int     `json:"repeat_of,omitempty"`
+	Times    int      `json:"times,omitempty"`
+}
+
+// Text is the section's lines without its label.
+func (s LyricsSection) Text() string {
+	return strings.Join(s.Lines, "\n")
+}
+
+// ParseLyrics splits lyrics into sections. Sections are separated by blank
+// lines or start at a label line such as "Chorus:", "[Bridge]" or "Verse 2".
+// A label stands alone on its line, followed at most by a repeat count, so
+// a lyric line such as "Intro: the night begins" is kept as text. Paragraphs
+// without a label are verses. Both LF and CRLF line endings are accepted.
+func ParseLyrics(text string) []LyricsSection {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	sections := []LyricsSection{}
+	var current *LyricsSection
+	closed := true
+	start := func(section LyricsSection) {
+		section.Index = len(sections)
+		section.Lines = []string{}
+		sections = append(sections, section)
+		current = &sections[len(sections)-1]
+		closed = false
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			closed = true
+			continue
+		}
+		if section, ok := parseSectionLabel(line); ok {
+			start(section)
+			continue
+		}
+		if closed {
+			start(LyricsSection{Type: SectionVerse})
+		}
+		current.Lines = append(current.Lines, line)
+	}
+
+	numberSections(sections)
+	return sections
+}
+
+// parseSectionLabel recognises a label line and returns the section it starts.
+func parseSectionLabel(line string) (LyricsSection, bool) {
+	label := line
+	switch {
+	case len(line) > 2 && (line[0] == '[' && line[len(line)-1] == ']' || line[0] == '(' && line[len(line)-1] == ')'):
+		label = strings.TrimSpace(line[1 : len(line)-1])
+		label = strings.TrimSuffix(label, ":")
+	case strings.Contains(line, ":"):
+		name, rest, _ := strings.Cut(line, ":")
+		label = strings.TrimSpace(name)
+		if rest = strings.TrimSpace(rest); rest != "" {
+			if !repeatMarker.MatchString(rest) {
+				return LyricsSection{}, false
+			}
+			label += " " + rest
+		}
+	}
+
+	match := sectionLabel.FindStringSubmatch(label)
+	if match == nil {
+		return LyricsSection{}, false
+	}
+	section := LyricsSection{Type: sectionTypes[strings.ToLower(match[1])], Label: label}
+	section.Number, _ = strconv.Atoi(match[2])
+	if times, _ := strconv.Atoi(match[3]); times > 1 {
+		section.Times = times
+	}
+	return section, true
+}
+
+// numberSections numbers sections within their type and links label-only
+// sections to the section they repeat.
+func numberSections(sections []LyricsSection) {
+	next := make(map[string]int)
+	for i := range sections {
+		section := &sections[i]
+		if len(section.Lines) == 0 && section.Label != "" {
+			if source := findRepeated(sections[:i], section.Type, section.Number); source >= 0 {
+				section.RepeatOf = &source
+				section.Number = sections[source].Number
+				continue
+			}
+		}
+		if section.Number == 0 {
+			section.Number = next[section.Type] + 1
+		}
+		next[section.Type] = max(next[section.Type], section.Number)
+	}
+}
+
+// findRepeated returns the index of the last section with lines of the given
+// type, and number unless it is 0, or -1 if there is none.
+func findRepeated(sections []LyricsSection, sectionType string, number int) int {
+	for i := len(sections) - 1; i >= 0; i-- {
+		section := sections[i]
+		if section.Type == sectionType && len(section.Lines) > 0 && (number == 0 || section.Number == number) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ExpandLyrics fills in the lines of sections that repeat an earlier one,
+// written out as many times as the label asks for.
+func ExpandLyrics(sections []LyricsSection) []LyricsSection {
+	expanded := make([]LyricsSection, len(sections))
+	for i, section := range sections {
+		lines := section.Lines
+		if section.RepeatOf != nil {
+			lines = sections[*section.RepeatOf].Lines
+		}
+		section.Lines = repeatLines(lines, section.Times)
+		expanded[i] = section
+	}
+	return expanded
+}
+
+// LyricsVerses lists the text of every section with lines, without labels.
+// Pass expanded sections to have repeats written out.
+func LyricsVerses(sections []LyricsSection) []string {
+	verses := make([]string, 0, len(sections))
+	for _, section := range sections {
+		if len(section.Lines) > 0 {
+			verses = append(verses, section.Text())
+		}
+	}
+	return verses
+}
+
+// repeatLines returns a copy of lines written out the given number of times.
+func repeatLines(lines []string, times int) []string {
+	repeated := make([]string, 0, len(lines)*max(times, 1))
+	for range max(times, 1) {
+		repeated = append(repeated, lines...)
+	}
+	return repeated
+}
+
+// SyncedLyrics is a song's time-synced lyrics, stored as normalized LRC. The
+// song's plain lyrics are derived from them and replace them when uploaded;
+// editing the plain lyrics afterwards drops the synced version.
+type SyncedLyrics struct {
+	SongID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"song_id"`
+	LRC       string    `gorm:"type:text;not null" json:"lrc"`
+	Enhanced  bool      `gorm:"not null;default:false" json:"enhanced"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LyricsPosition is what is being sung at a moment of a song: the current line
+// and word, if any, and the line that comes next.
+type LyricsPosition struct {
+	Time      lrc.Timestamp `json:"time" swaggertype:"number"`
+	LineIndex int           `json:"line_index"`
+	Line      *lrc.Line     `json:"line"`
+	WordIndex int           `json:"word_index"`
+	Word      *lrc.Word     `json:"word"`
+	Next      *lrc.Line     `json:"next"`
+}
+
+// PositionAt finds the line and word of the lyrics being sung at t. Indexes
+// are -1 when t is before the first line or the line's first word.
+func PositionAt(lyrics *lrc.Lyrics, t lrc.Timestamp) *LyricsPosition {
+	line, word := lyrics.At(t)
+	position := &LyricsPosition{Time: t, LineIndex: line, WordIndex: word}
+	if line >= 0 {
+		position.Line = &lyrics.Lines[line]
+	}
+	if word >= 0 {
+		position.Word = &lyrics.Lines[line].Words[word]
+	}
+	if line+1 < len(lyrics.Lines) {
+		position.Next = &lyrics.Lines[line+1]
+	}
+	return position
+}
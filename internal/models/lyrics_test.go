@@ -0,0 +1,148 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// section describes a parsed section for comparison, leaving out Index.
+type section struct {
+	Type     string
+	Number   int
+	Label    string
+	Lines    []string
+	RepeatOf int
+	Times    int
+}
+
+func summarize(sections []LyricsSection) []section {
+	got := make([]section, len(sections))
+	for i, s := range sections {
+		got[i] = section{Type: s.Type, Number: s.Number, Label: s.Label, Lines: s.Lines, RepeatOf: -1, Times: s.Times}
+		if s.RepeatOf != nil {
+			got[i].RepeatOf = *s.RepeatOf
+		}
+	}
+	return got
+}
+
+func TestParseLyrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		lyrics string
+		want   []section
+	}{
+		{
+			name:   "paragraphs are verses",
+			lyrics: "one\ntwo\n\nthree",
+			want: []section{
+				{Type: SectionVerse, Number: 1, Lines: []string{"one", "two"}, RepeatOf: -1},
+				{Type: SectionVerse, Number: 2, Lines: []string{"three"}, RepeatOf: -1},
+			},
+		},
+		{
+			name:   "CRLF and CR line endings",
+			lyrics: "one\r\ntwo\r\n\r\nChorus:\r\nla la\rla",
+			want: []section{
+				{Type: SectionVerse, Number: 1, Lines: []string{"one", "two"}, RepeatOf: -1},
+				{Type: SectionChorus, Number: 1, Label: "Chorus", Lines: []string{"la la", "la"}, RepeatOf: -1},
+			},
+		},
+		{
+			name:   "bracketed and parenthesized labels",
+			lyrics: "[Intro]\nhey\n(Bridge:)\nso\n[ Pre-Chorus ]\nup",
+			want: []section{
+				{Type: SectionIntro, Number: 1, Label: "Intro", Lines: []string{"hey"}, RepeatOf: -1},
+				{Type: SectionBridge, Number: 1, Label: "Bridge", Lines: []string{"so"}, RepeatOf: -1},
+				{Type: SectionPreChorus, Number: 1, Label: "Pre-Chorus", Lines: []string{"up"}, RepeatOf: -1},
+			},
+		},
+		{
+			name:   "label starts a section without a blank line",
+			lyrics: "one\nVerse 2\ntwo\nrefrain:\nla",
+			want: []section{
+				{Type: SectionVerse, Number: 1, Lines: []string{"one"}, RepeatOf: -1},
+				{Type: SectionVerse, Number: 2, Label: "Verse 2", Lines: []string{"two"}, RepeatOf: -1},
+				{Type: SectionChorus, Number: 1, Label: "refrain", Lines: []string{"la"}, RepeatOf: -1},
+			},
+		},
+		{
+			name:   "text after a colon is a lyric line",
+			lyrics: "Intro: the night begins\nChorus: sing along\n\nOutro:\nbye",
+			want: []section{
+				{Type: SectionVerse, Number: 1, Lines: []string{"Intro: the night begins", "Chorus: sing along"}, RepeatOf: -1},
+				{Type: SectionOutro, Number: 1, Label: "Outro", Lines: []string{"bye"}, RepeatOf: -1},
+			},
+		},
+		{
+			name:   "repeat markers",
+			lyrics: "Chorus:\nla\n\nVerse:\nv\n\n[Chorus x2]\n\nChorus: ×3\n\nChorus",
+			want: []section{
+				{Type: SectionChorus, Number: 1, Label: "Chorus", Lines: []string{"la"}, RepeatOf: -1},
+				{Type: SectionVerse, Number: 1, Label: "Verse", Lines: []string{"v"}, RepeatOf: -1},
+				{Type: SectionChorus, Number: 1, Label: "Chorus x2", Lines: []string{}, RepeatOf: 0, Times: 2},
+				{Type: SectionChorus, Number: 1, Label: "Chorus ×3", Lines: []string{}, RepeatOf: 0, Times: 3},
+				{Type: SectionChorus, Number: 1, Label: "Chorus", Lines: []string{}, RepeatOf: 0},
+			},
+		},
+		{
+			name:   "numbered repeat",
+			lyrics: "Chorus 1:\na\n\nChorus 2:\nb\n\nChorus 1",
+			want: []section{
+				{Type: SectionChorus, Number: 1, Label: "Chorus 1", Lines: []string{"a"}, RepeatOf: -1},
+				{Type: SectionChorus, Number: 2, Label: "Chorus 2", Lines: []string{"b"}, RepeatOf: -1},
+				{Type: SectionChorus, Number: 1, Label: "Chorus 1", Lines: []string{}, RepeatOf: 0},
+			},
+		},
+		{
+			name:   "label with nothing to repeat",
+			lyrics: "[Bridge]\n\nla",
+			want: []section{
+				{Type: SectionBridge, Number: 1, Label: "Bridge", Lines: []string{}, RepeatOf: -1},
+				{Type: SectionVerse, Number: 1, Lines: []string{"la"}, RepeatOf: -1},
+			},
+		},
+		{name: "empty", lyrics: "\n \r\n", want: []section{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections := ParseLyrics(tt.lyrics)
+			if got := summarize(sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLyrics =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			for i, s := range sections {
+				if s.Index != i {
+					t.Errorf("section %d has Index %d", i, s.Index)
+				}
+			}
+		})
+	}
+}
+
+func TestLyricsVerses(t *testing.T) {
+	// GET /songs/:id/lyrics used to split on blank lines and return labels
+	// with the text. It now pages through section texts: labels are left
+	// off, a label starts a new entry, and repeats are written out.
+	lyrics := "Verse 1:\nfirst\nsecond\nChorus:\nla la\n\n[Chorus x2]\n\nlast"
+	got := LyricsVerses(ExpandLyrics(ParseLyrics(lyrics)))
+	want := []string{"first\nsecond", "la la", "la la\nla la", "last"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LyricsVerses = %q, want %q", got, want)
+	}
+
+	// Unexpanded, label-only repeats have no text and are skipped.
+	got = LyricsVerses(ParseLyrics(lyrics))
+	want = []string{"first\nsecond", "la la", "last"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LyricsVerses unexpanded = %q, want %q", got, want)
+	}
+}
+
+func TestExpandLyricsCopiesLines(t *testing.T) {
+	sections := ParseLyrics("Chorus:\nla\n\nChorus")
+	expanded := ExpandLyrics(sections)
+	expanded[1].Lines[0] = "changed"
+	if sections[0].Lines[0] != "la" {
+		t.Errorf("expanding shares lines with the repeated section; it now has %q", sections[0].Lines)
+	}
+}
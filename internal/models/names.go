@@ -0,0 +1,14 @@
+package models
+
+import "strings"
+
+// CleanName trims an artist or group name and collapses runs of whitespace.
+func CleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeName folds case and whitespace so that trivially different
+// spellings of a name resolve to the same artist or group.
+func NormalizeName(name string) string {
+	return strings.ToLower(CleanName(name))
+}
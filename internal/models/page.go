@@ -0,0 +1,47 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// PageRequest asks for one page of a keyset-paginated song listing.
+// Sort is a column name, prefixed with "-" for descending order; Cursor is the
+// opaque token returned as next_cursor or prev_cursor by a previous page.
+type PageRequest struct {
+	Limit  int
+	Sort   string
+	Cursor string
+}
+
+// SongPage is one page of a song listing along with the cursors to its neighbours.
+type SongPage struct {
+	Songs      []Song `json:"songs"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+
+	// TagFacets counts the tags across every song matching the listing, not just this page.
+	TagFacets []TagFacet `json:"tag_facets,omitempty"`
+}
+
+// ListingState summarizes the songs a listing selects, so a client's copy of
+// the listing can be revalidated without loading it. Any change to a song
+// moves it to a new version and updated_at, and songs entering or leaving the
+// listing change the count.
+type ListingState struct {
+	Count     int64     `json:"count"`
+	Versions  int64     `json:"versions"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// LastModified is when any song last changed, whether listed or not, since
+	// a song leaving a listing does not show in the listing's own updated_at.
+	LastModified time.Time `json:"last_modified"`
+}
+
+// ETag is a weak entity tag that changes whenever the listed songs do.
+func (s ListingState) ETag() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d", s.Count, s.Versions, s.UpdatedAt.UnixMicro())))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
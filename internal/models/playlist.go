@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Playlist is a user-curated, ordered list of songs. Private playlists are
+// only visible to their owner.
+type Playlist struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	OwnerID     *uuid.UUID      `gorm:"type:uuid;index" json:"owner_id" binding:"-"`
+	Name        string          `gorm:"not null" json:"name" binding:"required"`
+	Description string          `json:"description"`
+	IsPublic    bool            `gorm:"not null;default:false;index" json:"is_public"`
+	Entries     []PlaylistEntry `gorm:"foreignKey:PlaylistID;constraint:OnDelete:CASCADE" json:"entries,omitempty" binding:"-"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// PlaylistEntry places a song in a playlist. Entries are ordered by Position,
+// a fractional key, so an entry can be moved by rewriting only its own key.
+type PlaylistEntry struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	PlaylistID uuid.UUID `gorm:"type:uuid;not null;index:idx_playlist_entries_order,priority:1" json:"playlist_id"`
+	SongID     uuid.UUID `gorm:"type:uuid;not null;index" json:"song_id"`
+	Position   float64   `gorm:"not null;index:idx_playlist_entries_order,priority:2" json:"position"`
+	AddedAt    time.Time `gorm:"autoCreateTime" json:"added_at"`
+	Song       *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"song,omitempty"`
+}
+
+// EntryPosition places an entry right after or right before another entry of
+// the same playlist. With neither set the entry goes to the end.
+type EntryPosition struct {
+	After  *uuid.UUID `json:"after"`
+	Before *uuid.UUID `json:"before"`
+}
+
+// Beside reports whether the position is right after or right before the entry id.
+func (p EntryPosition) Beside(id uuid.UUID) bool {
+	return (p.After != nil && *p.After == id) || (p.Before != nil && *p.Before == id)
+}
+
+// PlaylistEntryInput adds a song to a playlist at the given position.
+type PlaylistEntryInput struct {
+	SongID uuid.UUID `json:"song_id" binding:"required"`
+	EntryPosition
+}
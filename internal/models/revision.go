@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/pkg/textdiff"
+)
+
+// SongState is the editable content of a song at some point in time.
+type SongState struct {
+	Name        string     `json:"name"`
+	Group       string     `json:"group"`
+	GroupID     *uuid.UUID `json:"group_id"`
+	Artists     []string   `json:"artists"`
+	Lyrics      string     `json:"lyrics"`
+	Link        string     `json:"link"`
+	ReleaseDate time.Time  `json:"release_date"`
+}
+
+// Value stores the state as JSON.
+func (s SongState) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan reads the state back from JSON.
+func (s *SongState) Scan(value any) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, s)
+	case string:
+		return json.Unmarshal([]byte(data), s)
+	default:
+		return fmt.Errorf("cannot scan %T into SongState", value)
+	}
+}
+
+// Validate checks the state can be saved as a song.
+func (s SongState) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSong)
+	}
+	for _, artist := range s.Artists {
+		if strings.TrimSpace(artist) == "" {
+			return fmt.Errorf("%w: artist names cannot be empty", ErrInvalidSong)
+		}
+	}
+	if s.Link != "" {
+		link, err := url.Parse(s.Link)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return fmt.Errorf("%w: link must be an http or https URL", ErrInvalidSong)
+		}
+	}
+	return nil
+}
+
+// State returns the song's editable content.
+func (s *Song) State() SongState {
+	return SongState{
+		Name:        s.Name,
+		Group:       s.Group,
+		GroupID:     s.GroupID,
+		Artists:     append([]string(nil), s.Artists...),
+		Lyrics:      s.Lyrics,
+		Link:        s.Link,
+		ReleaseDate: s.ReleaseDate,
+	}
+}
+
+// ApplyState overwrites the song's editable content with state.
+func (s *Song) ApplyState(state SongState) {
+	s.Name = state.Name
+	s.Group = state.Group
+	s.GroupID = state.GroupID
+	s.Artists = append(s.Artists[:0:0], state.Artists...)
+	s.Lyrics = state.Lyrics
+	s.Link = state.Link
+	s.ReleaseDate = state.ReleaseDate
+}
+
+// Author is who made a change: a signed-in user or an API key.
+type Author struct {
+	UserID   *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	APIKeyID *uuid.UUID `gorm:"type:uuid" json:"api_key_id,omitempty"`
+}
+
+// SongRevision is an immutable record of a song's state before an update.
+// Revisions of a song are numbered from 1 in the order they were made.
+type SongRevision struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SongID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_song_revisions_number" json:"song_id"`
+	Number    int       `gorm:"not null;uniqueIndex:idx_song_revisions_number" json:"number"`
+	Author    Author    `gorm:"embedded;embeddedPrefix:author_" json:"author"`
+	State     SongState `gorm:"type:jsonb;not null" json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FieldChange is a field whose value differs between two song states.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// SongDiff compares two states of a song. To is nil when comparing against the current state.
+type SongDiff struct {
+	From   int             `json:"from"`
+	To     *int            `json:"to"`
+	Fields []FieldChange   `json:"fields"`
+	Lyrics []textdiff.Line `json:"lyrics"`
+}
+
+// DiffSongStates lists the fields that changed from a to b, with a line diff of the lyrics.
+func DiffSongStates(a, b SongState) ([]FieldChange, []textdiff.Line) {
+	fields := []FieldChange{}
+	add := func(field string, from, to any, changed bool) {
+		if changed {
+			fields = append(fields, FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	add("name", a.Name, b.Name, a.Name != b.Name)
+	add("group", a.Group, b.Group, a.Group != b.Group)
+	add("group_id", a.GroupID, b.GroupID, !equalUUIDs(a.GroupID, b.GroupID))
+	add("artists", a.Artists, b.Artists, !equalStrings(a.Artists, b.Artists))
+	add("lyrics", a.Lyrics, b.Lyrics, a.Lyrics != b.Lyrics)
+	add("link", a.Link, b.Link, a.Link != b.Link)
+	add("release_date", a.ReleaseDate, b.ReleaseDate, !a.ReleaseDate.Equal(b.ReleaseDate))
+
+	return fields, textdiff.Lines(textdiff.SplitLines(a.Lyrics), textdiff.SplitLines(b.Lyrics))
+}
+
+func equalUUIDs(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/pkg/textdiff"
+)
+
+func TestDiffSongStates(t *testing.T) {
+	group := uuid.New()
+	released := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	a := SongState{
+		Name:        "Song",
+		Group:       "Band",
+		GroupID:     &group,
+		Artists:     []string{"One", "Two"},
+		Lyrics:      "first\nsecond\nthird",
+		Link:        "https://example.com/a",
+		ReleaseDate: released,
+	}
+
+	fields, lyrics := DiffSongStates(a, a)
+	if len(fields) != 0 {
+		t.Errorf("fields of an unchanged song = %+v, want none", fields)
+	}
+	for _, line := range lyrics {
+		if line.Op != textdiff.Equal {
+			t.Errorf("unchanged lyrics diff has %+v", line)
+		}
+	}
+
+	b := a
+	sameGroup := group
+	b.GroupID = &sameGroup
+	b.Artists = []string{"One", "Three"}
+	b.Lyrics = "first\n2nd\nthird"
+	b.ReleaseDate = released.In(time.FixedZone("UTC+5", 5*60*60))
+	fields, lyrics = DiffSongStates(a, b)
+
+	var changed []string
+	for _, field := range fields {
+		changed = append(changed, field.Field)
+	}
+	// The group ID and release date are compared by value, not by pointer or zone.
+	if want := []string{"artists", "lyrics"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed fields = %v, want %v", changed, want)
+	}
+	if fields[0].From.([]string)[1] != "Two" || fields[0].To.([]string)[1] != "Three" {
+		t.Errorf("artists change = %+v", fields[0])
+	}
+	want := []textdiff.Line{
+		{Op: textdiff.Equal, Text: "first"},
+		{Op: textdiff.Delete, Text: "second"},
+		{Op: textdiff.Insert, Text: "2nd"},
+		{Op: textdiff.Equal, Text: "third"},
+	}
+	if !reflect.DeepEqual(lyrics, want) {
+		t.Errorf("lyrics diff = %+v, want %+v", lyrics, want)
+	}
+
+	b.GroupID = nil
+	b.ReleaseDate = released.AddDate(0, 0, 1)
+	fields, _ = DiffSongStates(a, b)
+	changed = changed[:0]
+	for _, field := range fields {
+		changed = append(changed, field.Field)
+	}
+	if want := []string{"group_id", "artists", "lyrics", "release_date"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed fields = %v, want %v", changed, want)
+	}
+}
+
+func TestApplyStateRestoresRevision(t *testing.T) {
+	group := uuid.New()
+	song := Song{ID: uuid.New(), Name: "Old", GroupID: &group, Artists: []string{"One"}, Lyrics: "old", Version: 3}
+	revision := song.State()
+
+	song.Name = "New"
+	song.GroupID = nil
+	song.Artists[0] = "Changed"
+	song.Lyrics = "new"
+	if revision.Artists[0] != "One" {
+		t.Fatalf("State shares the artists with the song; revision has %q", revision.Artists)
+	}
+
+	song.ApplyState(revision)
+	if got := song.State(); !reflect.DeepEqual(got, revision) {
+		t.Errorf("state after rollback = %+v, want %+v", got, revision)
+	}
+	if song.Version != 3 {
+		t.Errorf("Version = %d, want it left to the update", song.Version)
+	}
+	song.Artists[0] = "Again"
+	if revision.Artists[0] != "One" {
+		t.Errorf("ApplyState shares the artists with the revision; revision has %q", revision.Artists)
+	}
+}
+
+func TestSongStateRoundTrip(t *testing.T) {
+	group := uuid.New()
+	state := SongState{Name: "Song", GroupID: &group, Artists: []string{"One"}, ReleaseDate: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)}
+	value, err := state.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stored := range []any{value, string(value.([]byte))} {
+		var got SongState
+		if err := got.Scan(stored); err != nil {
+			t.Fatalf("Scan(%T): %v", stored, err)
+		}
+		if !reflect.DeepEqual(got, state) {
+			t.Errorf("Scan(%T) = %+v, want %+v", stored, got, state)
+		}
+	}
+	if err := new(SongState).Scan(42); err == nil {
+		t.Error("Scan(42) succeeded, want an error")
+	}
+}
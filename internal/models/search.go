@@ -0,0 +1,9 @@
+package models
+
+// SearchResult is a song matched by full-text search, with its relevance and the
+// best matching verse highlighted with <mark> tags.
+type SearchResult struct {
+	Song    `gorm:"embedded"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet,omitempty"`
+}
@@ -1,19 +1,66 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/ruziba3vich/music_lib/pkg/langdetect"
 )
 
 type Song struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	Artists     pq.StringArray `gorm:"type:text[]" json:"artists"`
 	Group       string         `gorm:"not null" json:"group"`
+	GroupID     *uuid.UUID     `gorm:"type:uuid;index" json:"group_id"`
 	Name        string         `gorm:"not null" json:"name"`
 	Lyrics      string         `gorm:"type:text" json:"lyrics"`
+	Language    string         `gorm:"not null;default:'';index" json:"language"`
+	Link        string         `json:"link"`
 	IsDeleted   bool           `gorm:"default:false" json:"-"`
+	DeletedAt   *time.Time     `gorm:"index" json:"deleted_at,omitempty"`
 	ReleaseDate time.Time      `json:"release_date"`
+	Version     int            `gorm:"not null;default:1" json:"version"`
 	CreatedAt   time.Time
+	UpdatedAt   time.Time `gorm:"index" json:"updated_at"`
+}
+
+// DetectLanguage sets the song's language from its lyrics, or from its name
+// when the lyrics are too short to tell. It is left empty when neither is.
+func (s *Song) DetectLanguage() {
+	s.Language = langdetect.Detect(s.Lyrics)
+	if s.Language == "" {
+		s.Language = langdetect.Detect(s.Name)
+	}
+}
+
+// VersionCondition lists the versions a write may replace, as read from an
+// If-Match header. A nil condition allows any version.
+type VersionCondition []int
+
+// Allows reports whether a song at version may be written.
+func (c VersionCondition) Allows(version int) bool {
+	if c == nil {
+		return true
+	}
+	for _, allowed := range c {
+		if allowed == version {
+			return true
+		}
+	}
+	return false
+}
+
+// ETag is the entity tag of the song's current version.
+func (s *Song) ETag() string {
+	return fmt.Sprintf(`"v%d"`, s.Version)
+}
+
+// SongDetails is a song together with its classification and the releases it appears on.
+type SongDetails struct {
+	Song
+	Genres   []Genre          `json:"genres"`
+	Tags     []string         `json:"tags"`
+	Releases []SongAppearance `json:"releases"`
 }
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"golang.org/x/text/language"
+)
+
+// CanonicalLanguage validates a BCP 47 language tag and returns its canonical
+// form, e.g. "en-US" for "en_us".
+func CanonicalLanguage(tag string) (string, error) {
+	parsed, err := language.Parse(tag)
+	if err != nil || parsed == language.Und {
+		return "", ErrInvalidLanguage
+	}
+	return parsed.String(), nil
+}
+
+// SongTranslation is a song's lyrics in another language, one translated line
+// per line of the original. SourceHash identifies the original lines it was
+// aligned with; when the lyrics change, the translation is Outdated.
+type SongTranslation struct {
+	SongID     uuid.UUID      `gorm:"type:uuid;primaryKey" json:"song_id"`
+	Language   string         `gorm:"primaryKey" json:"language"`
+	Lines      pq.StringArray `gorm:"type:text[];not null" json:"lines" swaggertype:"array,string"`
+	SourceHash string         `gorm:"not null" json:"-"`
+	Outdated   bool           `gorm:"-" json:"outdated"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// TranslationInput is a translation as lines aligned with the original, or
+// as text laid out like the original, with section labels and blank lines
+// ignored.
+type TranslationInput struct {
+	Lines []string `json:"lines"`
+	Text  string   `json:"text"`
+}
+
+// TranslationLines returns the translated lines of the input.
+func (in TranslationInput) TranslationLines() []string {
+	if in.Lines != nil {
+		return in.Lines
+	}
+	return LyricsLines(ParseLyrics(in.Text))
+}
+
+// TranslatedVerse is a section of the lyrics next to its translation.
+// Outdated is set when the translation was aligned with earlier lyrics, so
+// its lines may no longer match the original.
+type TranslatedVerse struct {
+	Original    string `json:"original"`
+	Translation string `json:"translation"`
+	Outdated    bool   `json:"outdated"`
+}
+
+// LyricsLines lists the lines of the sections in order, without labels or
+// blank lines. Translations are aligned with these lines.
+func LyricsLines(sections []LyricsSection) []string {
+	lines := []string{}
+	for _, section := range sections {
+		lines = append(lines, section.Lines...)
+	}
+	return lines
+}
+
+// LinesHash identifies a list of lines.
+func LinesHash(lines []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// TranslateSections pairs every section of the expanded lyrics that has lines
+// with its translation. Repeated sections reuse the translation of the section
+// they repeat; lines without a translation are left empty.
+func TranslateSections(sections []LyricsSection, translation []string) []TranslatedVerse {
+	offsets := make([]int, len(sections))
+	offset := 0
+	for i, section := range sections {
+		offsets[i] = offset
+		offset += len(section.Lines)
+	}
+	translated := func(section LyricsSection) []string {
+		lines := make([]string, len(section.Lines))
+		for i := range lines {
+			if index := offsets[section.Index] + i; index < len(translation) {
+				lines[i] = translation[index]
+			}
+		}
+		return lines
+	}
+
+	verses := []TranslatedVerse{}
+	expanded := ExpandLyrics(sections)
+	for i, section := range sections {
+		source := section
+		if section.RepeatOf != nil {
+			source = sections[*section.RepeatOf]
+		}
+		if len(expanded[i].Lines) == 0 {
+			continue
+		}
+		verses = append(verses, TranslatedVerse{
+			Original:    expanded[i].Text(),
+			Translation: strings.Join(repeatLines(translated(source), section.Times), "\n"),
+		})
+	}
+	return verses
+}
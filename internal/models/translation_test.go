@@ -0,0 +1,71 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTranslateSections(t *testing.T) {
+	lyrics := "Verse 1:\none\ntwo\n\nChorus:\nla\n\n[Chorus x2]\n\nthree"
+	translation := []string{"uno", "dos", "la-la", "tres"}
+	want := []TranslatedVerse{
+		{Original: "one\ntwo", Translation: "uno\ndos"},
+		{Original: "la", Translation: "la-la"},
+		{Original: "la\nla", Translation: "la-la\nla-la"},
+		{Original: "three", Translation: "tres"},
+	}
+	if got := TranslateSections(ParseLyrics(lyrics), translation); !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateSections =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestTranslateSectionsOutdated(t *testing.T) {
+	// The translation was aligned with "one\ntwo\n\nthree". A line was added
+	// to the lyrics since, so lines are paired by position, the ones after
+	// the new line are shifted, and the last line has no translation.
+	original := ParseLyrics("one\ntwo\n\nthree")
+	translation := []string{"uno", "dos", "tres"}
+	input := SongTranslation{Lines: translation, SourceHash: LinesHash(LyricsLines(original))}
+
+	changed := ParseLyrics("one\nnew\ntwo\n\nthree")
+	if input.SourceHash == LinesHash(LyricsLines(changed)) {
+		t.Fatal("changed lyrics have the hash of the original")
+	}
+	want := []TranslatedVerse{
+		{Original: "one\nnew\ntwo", Translation: "uno\ndos\ntres"},
+		{Original: "three", Translation: ""},
+	}
+	if got := TranslateSections(changed, translation); !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateSections =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Removed lines leave translated lines over; they are dropped.
+	want = []TranslatedVerse{{Original: "one", Translation: "uno"}}
+	if got := TranslateSections(ParseLyrics("one"), translation); !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateSections of shorter lyrics =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestTranslationLines(t *testing.T) {
+	input := TranslationInput{Text: "[Verse]\nuno\r\ndos\n\n[Chorus]\nla"}
+	if got, want := input.TranslationLines(), []string{"uno", "dos", "la"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslationLines = %q, want %q", got, want)
+	}
+	input.Lines = []string{"given"}
+	if got := input.TranslationLines(); !reflect.DeepEqual(got, []string{"given"}) {
+		t.Errorf("TranslationLines with lines = %q, want the lines as given", got)
+	}
+}
+
+func TestCanonicalLanguage(t *testing.T) {
+	for tag, want := range map[string]string{"en_us": "en-US", "UZ-latn": "uz-Latn", "ru": "ru"} {
+		if got, err := CanonicalLanguage(tag); err != nil || got != want {
+			t.Errorf("CanonicalLanguage(%q) = %q, %v, want %q", tag, got, err, want)
+		}
+	}
+	for _, tag := range []string{"", "und", "not a tag"} {
+		if _, err := CanonicalLanguage(tag); err != ErrInvalidLanguage {
+			t.Errorf("CanonicalLanguage(%q) error = %v, want ErrInvalidLanguage", tag, err)
+		}
+	}
+}
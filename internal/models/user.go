@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// User roles, from least to most privileged.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ValidUserRole reports whether role is one of the known user roles.
+func ValidUserRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants everything required does.
+func RoleAtLeast(role, required string) bool {
+	return ValidUserRole(role) && roleRanks[role] >= roleRanks[required]
+}
+
+// User is an account that can sign in to the API.
+type User struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Username     string    `gorm:"not null;uniqueIndex" json:"username"`
+	PasswordHash string    `gorm:"not null" json:"-"`
+	Role         string    `gorm:"not null;default:viewer" json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Credentials are what a user registers and signs in with.
+type Credentials struct {
+	Username string `json:"username" binding:"required,max=64"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// Username length limits, counted in characters after normalization.
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 64
+)
+
+// Password length limits. bcrypt ignores everything past 72 bytes.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// Validate checks the normalized username and the password against the
+// length limits, for callers that do not go through request binding.
+func (c Credentials) Validate() error {
+	if n := utf8.RuneCountInString(NormalizeName(c.Username)); n < MinUsernameLength || n > MaxUsernameLength {
+		return ErrInvalidUsername
+	}
+	if n := len(c.Password); n < MinPasswordLength || n > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+// RefreshRequest exchanges a refresh token for a new token pair.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RoleUpdate changes a user's role.
+type RoleUpdate struct {
+	Role string `json:"role" binding:"required"`
+}
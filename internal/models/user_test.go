@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCredentialsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials Credentials
+		want        error
+	}{
+		{name: "valid", credentials: Credentials{Username: "alice", Password: "password"}},
+		{name: "spaces are collapsed", credentials: Credentials{Username: "  al   ice ", Password: "password"}},
+		{name: "only spaces", credentials: Credentials{Username: "     ", Password: "password"}, want: ErrInvalidUsername},
+		{name: "too short once normalized", credentials: Credentials{Username: "  ab  ", Password: "password"}, want: ErrInvalidUsername},
+		{name: "characters, not bytes", credentials: Credentials{Username: "жук", Password: "password"}},
+		{name: "too long", credentials: Credentials{Username: strings.Repeat("a", 65), Password: "password"}, want: ErrInvalidUsername},
+		{name: "short password", credentials: Credentials{Username: "alice", Password: "secret"}, want: ErrInvalidPassword},
+		{name: "password beyond bcrypt's limit", credentials: Credentials{Username: "alice", Password: strings.Repeat("p", 73)}, want: ErrInvalidPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.credentials.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package redisservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// Cached song pages are tagged with what they list, such as an artist's songs.
+// Every tag has a generation, a random value stored under tag:<tag>, and the key
+// a page is cached under includes the generations of its tags. Invalidating a
+// tag gives it a new generation, so the pages cached under the old one are never
+// read again and expire with the TTL. A listing read from the database while a
+// write commits is cached under the generation it started with, so it cannot
+// bring back a page the write invalidated.
+
+// PageKey returns the key the page identified by query is cached under, given
+// the current generations of its tags.
+func (r *RedisService) PageKey(ctx context.Context, query string, tags []string) (string, error) {
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, "tag:"+tag)
+	}
+	generations, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tag generations from redis: %v", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(query))
+	for _, generation := range generations {
+		value, _ := generation.(string)
+		hash.Write([]byte{0})
+		hash.Write([]byte(value))
+	}
+	return "songs:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetSongPage retrieves a cached page by the key from PageKey.
+func (r *RedisService) GetSongPage(ctx context.Context, key string) (*models.SongPage, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get song page from redis: %v", err)
+	}
+
+	var page models.SongPage
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal song page: %v", err)
+	}
+	return &page, nil
+}
+
+// AddSongPage caches a page under the key from PageKey with an expiration time.
+func (r *RedisService) AddSongPage(ctx context.Context, key string, page *models.SongPage) error {
+	data, err := json.Marshal(page)
+	if err != nil {
+		return fmt.Errorf("failed to marshal song page: %v", err)
+	}
+	return r.client.Set(ctx, key, data, r.ttl).Err()
+}
+
+// GetListingState retrieves a cached listing summary by the key from PageKey.
+func (r *RedisService) GetListingState(ctx context.Context, key string) (*models.ListingState, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get listing state from redis: %v", err)
+	}
+
+	var state models.ListingState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal listing state: %v", err)
+	}
+	return &state, nil
+}
+
+// AddListingState caches a listing summary under the key from PageKey with an expiration time.
+func (r *RedisService) AddListingState(ctx context.Context, key string, state *models.ListingState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal listing state: %v", err)
+	}
+	return r.client.Set(ctx, key, data, r.ttl).Err()
+}
+
+// InvalidatePages gives the tags new generations, dropping every page cached with any of them.
+func (r *RedisService) InvalidatePages(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for _, tag := range tags {
+		pipe.Set(ctx, "tag:"+tag, uuid.NewString(), 0)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to invalidate song pages in redis: %s", err.Error())
+	}
+	return nil
+}
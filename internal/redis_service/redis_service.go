@@ -50,18 +50,34 @@ func (r *RedisService) GetSong(ctx context.Context, songID string) (*models.Song
 	if err := json.Unmarshal([]byte(data), &song); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal song: %v", err)
 	}
+	// Copies cached before songs were versioned are treated as missing.
+	if song.Version == 0 {
+		return nil, nil
+	}
 
 	return &song, nil
 }
 
+// DeleteSong drops the cached copy of a song. A song that is not cached is not an error.
 func (r *RedisService) DeleteSong(ctx context.Context, songID string) error {
 	key := fmt.Sprintf("song:%s", songID)
-	deleted, err := r.client.Del(ctx, key).Result()
-	if err != nil {
+	if err := r.client.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete song from redis: %s", err.Error())
 	}
-	if deleted == 0 {
-		return fmt.Errorf("song with ID %s not found in cache", songID)
+	return nil
+}
+
+// InvalidateSongs drops the cached copies of the given songs, ignoring ones that are not cached.
+func (r *RedisService) InvalidateSongs(ctx context.Context, songIDs ...string) error {
+	if len(songIDs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(songIDs))
+	for _, id := range songIDs {
+		keys = append(keys, fmt.Sprintf("song:%s", id))
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate songs in redis: %s", err.Error())
 	}
 	return nil
 }
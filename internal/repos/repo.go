@@ -2,19 +2,115 @@ package repos
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/lrc"
 )
 
 type (
 	Repo interface {
 		CreateSong(context.Context, *models.Song) error
-		DeleteSong(context.Context, string) error
+		DeleteSong(context.Context, string, models.VersionCondition) error
 		GetSongByID(context.Context, string) (*models.Song, error)
 		GetSongLyricsPaginated(context.Context, string, int, int) ([]string, error)
-		GetSongsWithFilters(context.Context, map[string]any, int, int) ([]models.Song, error)
-		GetSongs(context.Context, int, int) ([]models.Song, error)
-		UpdateSong(context.Context, *models.Song) error
-		GetSongsByArtist(context.Context, string, int, int) ([]models.Song, error)
+		GetSongLyricsSections(context.Context, string, string, bool) ([]models.LyricsSection, error)
+		GetSongLyricsSection(context.Context, string, int, bool) (*models.LyricsSection, error)
+		SetSyncedLyrics(context.Context, string, string, models.Author, models.VersionCondition) (*models.Song, error)
+		GetSyncedLyrics(context.Context, string) (*lrc.Lyrics, error)
+		GetLyricsAt(context.Context, string, lrc.Timestamp) (*models.LyricsPosition, error)
+		DeleteSyncedLyrics(context.Context, string) error
+		SetSongTranslation(context.Context, string, string, []string) (*models.SongTranslation, error)
+		GetSongTranslations(context.Context, string) ([]models.SongTranslation, error)
+		GetSongTranslation(context.Context, string, string) (*models.SongTranslation, error)
+		GetTranslatedLyricsPaginated(context.Context, string, string, int, int) ([]models.TranslatedVerse, error)
+		DeleteSongTranslation(context.Context, string, string) error
+		GetSongsWithFilters(context.Context, models.SongFilter, models.PageRequest) (*models.SongPage, error)
+		GetSongs(context.Context, models.PageRequest) (*models.SongPage, error)
+		GetSongListingState(context.Context, models.SongFilter) (*models.ListingState, error)
+		UpdateSong(context.Context, *models.Song, models.Author, models.VersionCondition) error
+		PatchSong(context.Context, string, string, []byte, models.Author, models.VersionCondition) (*models.Song, error)
+		ImportSongs(context.Context, io.Reader, models.ImportOptions) (*models.ImportReport, error)
+		ExportSongs(context.Context, io.Writer, models.ExportOptions) (int, error)
+		GetSongsByArtist(context.Context, string, models.PageRequest) (*models.SongPage, error)
+		SearchSongs(context.Context, string, string, int, int) ([]models.SearchResult, error)
+		FuzzySearchSongs(context.Context, string, string, float64, int, int) ([]models.SearchResult, error)
+
+		CreateArtist(context.Context, *models.Artist) error
+		GetArtists(context.Context, int, int) ([]models.Artist, error)
+		GetArtistByID(context.Context, string) (*models.Artist, error)
+		UpdateArtist(context.Context, *models.Artist) error
+		DeleteArtist(context.Context, string) error
+		GetSongsByArtistID(context.Context, string, models.PageRequest) (*models.SongPage, error)
+		GetSongArtists(context.Context, string) ([]models.SongArtist, error)
+		SetSongArtists(context.Context, string, []models.SongArtistInput) ([]models.SongArtist, error)
+
+		CreateGroup(context.Context, *models.Group) error
+		GetGroups(context.Context, int, int) ([]models.Group, error)
+		GetGroupByID(context.Context, string) (*models.Group, error)
+		UpdateGroup(context.Context, *models.Group) error
+		DeleteGroup(context.Context, string) error
+		GetGroupMembers(context.Context, string) ([]models.GroupMember, error)
+		AddGroupMember(context.Context, *models.GroupMember) error
+		UpdateGroupMember(context.Context, *models.GroupMember) error
+		DeleteGroupMember(context.Context, string, string) error
+		GetGroupCatalogue(context.Context, string, time.Time, models.PageRequest) (*models.GroupCatalogue, error)
+
+		CreateAlbum(context.Context, *models.Album) error
+		GetAlbums(context.Context, int, int) ([]models.Album, error)
+		GetAlbumByID(context.Context, string) (*models.Album, error)
+		UpdateAlbum(context.Context, *models.Album) error
+		DeleteAlbum(context.Context, string) error
+		CreateRelease(context.Context, *models.Release) error
+		GetReleases(context.Context, int, int) ([]models.Release, error)
+		GetReleaseByID(context.Context, string) (*models.Release, error)
+		UpdateRelease(context.Context, *models.Release) error
+		DeleteRelease(context.Context, string) error
+		SetReleaseTracks(context.Context, string, []models.TrackInput) (*models.Release, error)
+		AddReleaseTrack(context.Context, string, models.TrackInput) (*models.Release, error)
+		MoveReleaseTrack(context.Context, string, string, models.TrackPosition) (*models.Release, error)
+		RemoveReleaseTrack(context.Context, string, string) (*models.Release, error)
+		GetSongReleases(context.Context, string) ([]models.SongAppearance, error)
+
+		CreateGenre(context.Context, *models.Genre) error
+		GetGenres(context.Context) ([]*models.Genre, error)
+		GetGenreByID(context.Context, string) (*models.Genre, error)
+		UpdateGenre(context.Context, *models.Genre) error
+		DeleteGenre(context.Context, string) error
+		GetSongGenres(context.Context, string) ([]models.Genre, error)
+		SetSongGenres(context.Context, string, []uuid.UUID) ([]models.Genre, error)
+		GetSongTags(context.Context, string) ([]string, error)
+		SetSongTags(context.Context, string, []string) ([]string, error)
+
+		GetSongRevisions(context.Context, string, int, int) ([]models.SongRevision, error)
+		GetSongRevision(context.Context, string, int) (*models.SongRevision, error)
+		DiffSongRevisions(context.Context, string, int, *int) (*models.SongDiff, error)
+		RollbackSong(context.Context, string, int, models.Author) (*models.Song, error)
+
+		GetTrash(context.Context, int, int) ([]models.Song, error)
+		RestoreSong(context.Context, string) (*models.Song, error)
+		PurgeSong(context.Context, string) error
+
+		CreatePlaylist(context.Context, *models.Playlist) error
+		GetPlaylists(context.Context, *uuid.UUID, int, int) ([]models.Playlist, error)
+		GetPlaylistByID(context.Context, string) (*models.Playlist, error)
+		UpdatePlaylist(context.Context, *models.Playlist) error
+		DeletePlaylist(context.Context, string) error
+		AddPlaylistEntry(context.Context, string, models.PlaylistEntryInput) (*models.PlaylistEntry, error)
+		MovePlaylistEntry(context.Context, string, string, models.EntryPosition) (*models.PlaylistEntry, error)
+		RemovePlaylistEntry(context.Context, string, string) error
+
+		Register(context.Context, models.Credentials) (*models.User, error)
+		Authenticate(context.Context, models.Credentials) (*models.User, error)
+		GetUserByID(context.Context, string) (*models.User, error)
+		GetUsers(context.Context, int, int) ([]models.User, error)
+		SetUserRole(context.Context, string, string) (*models.User, error)
+
+		CreateAPIKey(context.Context, models.APIKeyInput, *uuid.UUID) (*models.NewAPIKey, error)
+		GetAPIKeys(context.Context, int, int) ([]models.APIKey, error)
+		RevokeAPIKey(context.Context, string) (*models.APIKey, error)
+		AuthenticateAPIKey(context.Context, string) (*models.APIKey, error)
 	}
 )
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// CreateAlbum logs and calls storage.CreateAlbum
+func (s *Service) CreateAlbum(ctx context.Context, album *models.Album) error {
+	s.logger.Printf("INFO: Creating album: %+v", album)
+	err := s.storage.CreateAlbum(ctx, album)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create album: %v", err)
+	}
+	return err
+}
+
+// GetAlbums logs and calls storage.GetAlbums
+func (s *Service) GetAlbums(ctx context.Context, limit, offset int) ([]models.Album, error) {
+	s.logger.Printf("INFO: Fetching albums with (limit: %d, offset: %d)", limit, offset)
+	albums, err := s.storage.GetAlbums(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch albums: %v", err)
+	}
+	return albums, err
+}
+
+// GetAlbumByID logs and calls storage.GetAlbumByID
+func (s *Service) GetAlbumByID(ctx context.Context, id string) (*models.Album, error) {
+	s.logger.Printf("INFO: Fetching album ID: %s", id)
+	album, err := s.storage.GetAlbumByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch album ID %s: %v", id, err)
+	}
+	return album, err
+}
+
+// UpdateAlbum logs and calls storage.UpdateAlbum
+func (s *Service) UpdateAlbum(ctx context.Context, album *models.Album) error {
+	s.logger.Printf("INFO: Updating album ID %s", album.ID)
+	err := s.storage.UpdateAlbum(ctx, album)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update album ID %s: %v", album.ID, err)
+	}
+	return err
+}
+
+// DeleteAlbum logs and calls storage.DeleteAlbum
+func (s *Service) DeleteAlbum(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting album ID: %s", id)
+	err := s.storage.DeleteAlbum(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete album ID %s: %v", id, err)
+	}
+	return err
+}
+
+// CreateRelease logs and calls storage.CreateRelease
+func (s *Service) CreateRelease(ctx context.Context, release *models.Release) error {
+	s.logger.Printf("INFO: Creating release: %+v", release)
+	err := s.storage.CreateRelease(ctx, release)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create release: %v", err)
+	}
+	return err
+}
+
+// GetReleases logs and calls storage.GetReleases
+func (s *Service) GetReleases(ctx context.Context, limit, offset int) ([]models.Release, error) {
+	s.logger.Printf("INFO: Fetching releases with (limit: %d, offset: %d)", limit, offset)
+	releases, err := s.storage.GetReleases(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch releases: %v", err)
+	}
+	return releases, err
+}
+
+// GetReleaseByID logs and calls storage.GetReleaseByID
+func (s *Service) GetReleaseByID(ctx context.Context, id string) (*models.Release, error) {
+	s.logger.Printf("INFO: Fetching release ID: %s", id)
+	release, err := s.storage.GetReleaseByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch release ID %s: %v", id, err)
+	}
+	return release, err
+}
+
+// UpdateRelease logs and calls storage.UpdateRelease
+func (s *Service) UpdateRelease(ctx context.Context, release *models.Release) error {
+	s.logger.Printf("INFO: Updating release ID %s", release.ID)
+	err := s.storage.UpdateRelease(ctx, release)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update release ID %s: %v", release.ID, err)
+	}
+	return err
+}
+
+// DeleteRelease logs and calls storage.DeleteRelease
+func (s *Service) DeleteRelease(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting release ID: %s", id)
+	err := s.storage.DeleteRelease(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete release ID %s: %v", id, err)
+	}
+	return err
+}
+
+// SetReleaseTracks logs and calls storage.SetReleaseTracks
+func (s *Service) SetReleaseTracks(ctx context.Context, releaseID string, tracks []models.TrackInput) (*models.Release, error) {
+	s.logger.Printf("INFO: Setting tracklist of release ID %s: %+v", releaseID, tracks)
+	release, err := s.storage.SetReleaseTracks(ctx, releaseID, tracks)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set tracklist of release ID %s: %v", releaseID, err)
+	}
+	return release, err
+}
+
+// AddReleaseTrack logs and calls storage.AddReleaseTrack
+func (s *Service) AddReleaseTrack(ctx context.Context, releaseID string, track models.TrackInput) (*models.Release, error) {
+	s.logger.Printf("INFO: Adding track to release ID %s: %+v", releaseID, track)
+	release, err := s.storage.AddReleaseTrack(ctx, releaseID, track)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to add track to release ID %s: %v", releaseID, err)
+	}
+	return release, err
+}
+
+// MoveReleaseTrack logs and calls storage.MoveReleaseTrack
+func (s *Service) MoveReleaseTrack(ctx context.Context, releaseID, trackID string, position models.TrackPosition) (*models.Release, error) {
+	s.logger.Printf("INFO: Moving track ID %s of release ID %s to %+v", trackID, releaseID, position)
+	release, err := s.storage.MoveReleaseTrack(ctx, releaseID, trackID, position)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to move track ID %s of release ID %s: %v", trackID, releaseID, err)
+	}
+	return release, err
+}
+
+// RemoveReleaseTrack logs and calls storage.RemoveReleaseTrack
+func (s *Service) RemoveReleaseTrack(ctx context.Context, releaseID, trackID string) (*models.Release, error) {
+	s.logger.Printf("INFO: Removing track ID %s from release ID %s", trackID, releaseID)
+	release, err := s.storage.RemoveReleaseTrack(ctx, releaseID, trackID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to remove track ID %s from release ID %s: %v", trackID, releaseID, err)
+	}
+	return release, err
+}
+
+// GetSongReleases logs and calls storage.GetSongReleases
+func (s *Service) GetSongReleases(ctx context.Context, songID string) ([]models.SongAppearance, error) {
+	s.logger.Printf("INFO: Fetching releases of song ID: %s", songID)
+	appearances, err := s.storage.GetSongReleases(ctx, songID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch releases of song ID %s: %v", songID, err)
+	}
+	return appearances, err
+}
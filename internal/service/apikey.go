@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/auth"
+	"gorm.io/gorm"
+)
+
+// CreateAPIKey generates a key, stores its hash and returns the key once
+func (s *Service) CreateAPIKey(ctx context.Context, input models.APIKeyInput, createdBy *uuid.UUID) (*models.NewAPIKey, error) {
+	s.logger.Printf("INFO: Creating API key %q with scopes %v", input.Name, input.Scopes)
+	secret, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to generate API key: %v", err)
+		return nil, err
+	}
+	key := models.APIKey{
+		ID:        uuid.New(),
+		Name:      input.Name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashAPIKey(secret),
+		Scopes:    input.Scopes,
+		CreatedBy: createdBy,
+		ExpiresAt: input.ExpiresAt,
+	}
+	if err := s.storage.CreateAPIKey(ctx, &key); err != nil {
+		s.logger.Printf("ERROR: Failed to create API key: %v", err)
+		return nil, err
+	}
+	return &models.NewAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// GetAPIKeys logs and calls storage.GetAPIKeys
+func (s *Service) GetAPIKeys(ctx context.Context, limit, offset int) ([]models.APIKey, error) {
+	s.logger.Printf("INFO: Fetching API keys with (limit: %d, offset: %d)", limit, offset)
+	keys, err := s.storage.GetAPIKeys(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch API keys: %v", err)
+	}
+	return keys, err
+}
+
+// RevokeAPIKey logs and calls storage.RevokeAPIKey
+func (s *Service) RevokeAPIKey(ctx context.Context, id string) (*models.APIKey, error) {
+	s.logger.Printf("INFO: Revoking API key ID: %s", id)
+	key, err := s.storage.RevokeAPIKey(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to revoke API key ID %s: %v", id, err)
+	}
+	return key, err
+}
+
+// AuthenticateAPIKey looks the key up by its hash, rejects revoked and expired
+// keys and records the use
+func (s *Service) AuthenticateAPIKey(ctx context.Context, secret string) (*models.APIKey, error) {
+	key, err := s.storage.GetAPIKeyByHash(ctx, auth.HashAPIKey(secret))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Printf("ERROR: Failed to authenticate API key: unknown key")
+		return nil, models.ErrInvalidAPIKey
+	}
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch API key: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		s.logger.Printf("ERROR: Failed to authenticate API key ID %s: expired or revoked", key.ID)
+		return nil, models.ErrInvalidAPIKey
+	}
+	if err := s.storage.TouchAPIKey(ctx, key.ID, now); err != nil {
+		s.logger.Printf("ERROR: Failed to record use of API key ID %s: %v", key.ID, err)
+	}
+	return key, nil
+}
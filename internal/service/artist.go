@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// CreateArtist logs and calls storage.CreateArtist
+func (s *Service) CreateArtist(ctx context.Context, artist *models.Artist) error {
+	s.logger.Printf("INFO: Creating artist: %+v", artist)
+	err := s.storage.CreateArtist(ctx, artist)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create artist: %v", err)
+	}
+	return err
+}
+
+// GetArtists logs and calls storage.GetArtists
+func (s *Service) GetArtists(ctx context.Context, limit, offset int) ([]models.Artist, error) {
+	s.logger.Printf("INFO: Fetching artists with (limit: %d, offset: %d)", limit, offset)
+	artists, err := s.storage.GetArtists(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch artists: %v", err)
+	}
+	return artists, err
+}
+
+// GetArtistByID logs and calls storage.GetArtistByID
+func (s *Service) GetArtistByID(ctx context.Context, id string) (*models.Artist, error) {
+	s.logger.Printf("INFO: Fetching artist ID: %s", id)
+	artist, err := s.storage.GetArtistByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch artist ID %s: %v", id, err)
+	}
+	return artist, err
+}
+
+// UpdateArtist logs and calls storage.UpdateArtist
+func (s *Service) UpdateArtist(ctx context.Context, artist *models.Artist) error {
+	s.logger.Printf("INFO: Updating artist ID %s", artist.ID)
+	err := s.storage.UpdateArtist(ctx, artist)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update artist ID %s: %v", artist.ID, err)
+	}
+	return err
+}
+
+// DeleteArtist logs and calls storage.DeleteArtist
+func (s *Service) DeleteArtist(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting artist ID: %s", id)
+	err := s.storage.DeleteArtist(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete artist ID %s: %v", id, err)
+	}
+	return err
+}
+
+// GetSongsByArtistID logs and calls storage.GetSongsByArtistID
+func (s *Service) GetSongsByArtistID(ctx context.Context, id string, page models.PageRequest) (*models.SongPage, error) {
+	s.logger.Printf("INFO: Fetching songs for artist ID %s (limit: %d, sort: %q)", id, page.Limit, page.Sort)
+	songs, err := s.storage.GetSongsByArtistID(ctx, id, page)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch songs for artist ID %s: %v", id, err)
+	}
+	return songs, err
+}
+
+// GetSongArtists logs and calls storage.GetSongArtists
+func (s *Service) GetSongArtists(ctx context.Context, songID string) ([]models.SongArtist, error) {
+	s.logger.Printf("INFO: Fetching artists of song ID: %s", songID)
+	credits, err := s.storage.GetSongArtists(ctx, songID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch artists of song ID %s: %v", songID, err)
+	}
+	return credits, err
+}
+
+// SetSongArtists logs and calls storage.SetSongArtists
+func (s *Service) SetSongArtists(ctx context.Context, songID string, credits []models.SongArtistInput) ([]models.SongArtist, error) {
+	s.logger.Printf("INFO: Setting artists of song ID %s: %+v", songID, credits)
+	result, err := s.storage.SetSongArtists(ctx, songID, credits)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set artists of song ID %s: %v", songID, err)
+	}
+	return result, err
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/ruziba3vich/music_lib/internal/bulk"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// defaultImportBatchSize is the number of songs inserted per transaction when the caller does not choose.
+const defaultImportBatchSize = 500
+
+// ImportSongs reads songs from r and inserts the valid ones in batches, one
+// transaction per batch. Rows that are malformed or invalid are reported and
+// skipped. When a batch fails to insert, its rows are retried one by one so
+// only the offending rows are reported. A dry run validates without inserting.
+func (s *Service) ImportSongs(ctx context.Context, r io.Reader, options models.ImportOptions) (*models.ImportReport, error) {
+	s.logger.Printf("INFO: Importing songs: %+v", options)
+	reader, err := bulk.NewReader(r, options.Format)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to start import: %v", err)
+		return nil, err
+	}
+
+	report, err := importSongs(ctx, reader, options, s.storage.CreateSongs)
+	if err != nil {
+		s.logger.Printf("ERROR: Import stopped after %d rows: %v", report.Rows, err)
+		return report, err
+	}
+	s.logger.Printf("INFO: Imported %d of %d songs (dry run: %t)", report.Imported, report.Rows, report.DryRun)
+	return report, nil
+}
+
+// importSongs reads every record of reader and passes the valid songs to
+// create in batches. create inserts its songs in one transaction, all or none.
+func importSongs(ctx context.Context, reader bulk.Reader, options models.ImportOptions, create func(context.Context, []models.Song) error) (*models.ImportReport, error) {
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultImportBatchSize
+	}
+
+	report := &models.ImportReport{DryRun: options.DryRun, Errors: []models.ImportError{}}
+	fail := func(row int, err error) {
+		report.Failed++
+		report.Errors = append(report.Errors, models.ImportError{Row: row, Error: err.Error()})
+	}
+
+	var songs []models.Song
+	var rows []int
+	flush := func() error {
+		defer func() { songs, rows = songs[:0], rows[:0] }()
+		if len(songs) == 0 {
+			return nil
+		}
+		if options.DryRun {
+			report.Imported += len(songs)
+			return nil
+		}
+		// create fills in what it resolves, such as the ID of a group it
+		// created, which the rolled back batch no longer has. The rows are
+		// retried from their parsed values, so it works on a copy.
+		if err := create(ctx, slices.Clone(songs)); err == nil {
+			report.Imported += len(songs)
+			return nil
+		}
+		for i := range songs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := create(ctx, songs[i:i+1]); err != nil {
+				fail(rows[i], err)
+				continue
+			}
+			report.Imported++
+		}
+		return nil
+	}
+
+	for {
+		record, row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *bulk.RowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			fail(row, rowErr.Err)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+
+		report.Rows++
+		song, err := record.Song()
+		if err != nil {
+			fail(row, err)
+			continue
+		}
+		songs = append(songs, song)
+		rows = append(rows, row)
+		if len(songs) >= options.BatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, flush()
+}
+
+// ExportSongs writes the songs matching options.Filter to w in the requested
+// format as they are read from the database, and returns how many were written.
+func (s *Service) ExportSongs(ctx context.Context, w io.Writer, options models.ExportOptions) (int, error) {
+	s.logger.Printf("INFO: Exporting songs: %+v", options)
+	writer, err := bulk.NewWriter(w, options.Format, !options.OmitLyrics)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to start export: %v", err)
+		return 0, err
+	}
+
+	count := 0
+	err = s.storage.ExportSongs(ctx, options.Filter, !options.OmitLyrics, func(song *models.Song) error {
+		count++
+		return writer.Write(bulk.NewRecord(song, !options.OmitLyrics))
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		s.logger.Printf("ERROR: Export stopped after %d songs: %v", count, err)
+		return count, err
+	}
+
+	s.logger.Printf("INFO: Exported %d songs", count)
+	return count, nil
+}
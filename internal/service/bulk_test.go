@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/bulk"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// fakeStore inserts batches all or nothing, like storage.CreateSongs. Songs
+// named "bad" fail to insert. Before inserting, it resolves groups the way
+// the storage does, so a failed batch leaves group IDs behind in its songs.
+type fakeStore struct {
+	batches  [][]string
+	inserted []models.Song
+}
+
+func (f *fakeStore) create(_ context.Context, songs []models.Song) error {
+	var names []string
+	for i := range songs {
+		names = append(names, songs[i].Name)
+		if songs[i].GroupID == nil {
+			id := uuid.New()
+			songs[i].GroupID = &id
+		}
+	}
+	f.batches = append(f.batches, names)
+	for _, song := range songs {
+		if song.Name == "bad" {
+			return errors.New("duplicate key")
+		}
+	}
+	f.inserted = append(f.inserted, songs...)
+	return nil
+}
+
+func importNDJSON(t *testing.T, input string, options models.ImportOptions, store *fakeStore) *models.ImportReport {
+	t.Helper()
+	reader, err := bulk.NewReader(strings.NewReader(input), models.FormatNDJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := importSongs(context.Background(), reader, options, store.create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestImportRetriesFailedBatchRowByRow(t *testing.T) {
+	input := strings.Join([]string{
+		`{"name": "one"}`,
+		`{"name": "bad"}`,
+		`{"name": "three"}`,
+		`{"name": ""}`,
+		`{"name": "five"}`,
+		`not json`,
+		`{"name": "seven"}`,
+	}, "\n")
+	store := &fakeStore{}
+	report := importNDJSON(t, input, models.ImportOptions{Format: models.FormatNDJSON, BatchSize: 3}, store)
+
+	var inserted []string
+	for _, song := range store.inserted {
+		inserted = append(inserted, song.Name)
+	}
+	// The batch holding the bad row fails as a whole; its other rows are
+	// inserted on their own and later batches are unaffected.
+	if want := []string{"one", "three", "five", "seven"}; !reflect.DeepEqual(inserted, want) {
+		t.Errorf("inserted %v, want %v", inserted, want)
+	}
+	wantBatches := [][]string{{"one", "bad", "three"}, {"one"}, {"bad"}, {"three"}, {"five", "seven"}}
+	if !reflect.DeepEqual(store.batches, wantBatches) {
+		t.Errorf("batches = %v, want %v", store.batches, wantBatches)
+	}
+
+	if report.Rows != 7 || report.Imported != 4 || report.Failed != 3 {
+		t.Errorf("report = %+v, want 7 rows, 4 imported, 3 failed", report)
+	}
+	var failedRows []int
+	for _, e := range report.Errors {
+		failedRows = append(failedRows, e.Row)
+	}
+	if want := []int{2, 4, 6}; !reflect.DeepEqual(failedRows, want) {
+		t.Errorf("failed rows = %v, want %v", failedRows, want)
+	}
+}
+
+func TestImportRetryStartsFromParsedSongs(t *testing.T) {
+	store := &fakeStore{}
+	var resolved []uuid.UUID
+	create := func(ctx context.Context, songs []models.Song) error {
+		if len(songs) > 1 {
+			err := store.create(ctx, songs)
+			for _, song := range songs {
+				resolved = append(resolved, *song.GroupID)
+			}
+			return err
+		}
+		if songs[0].GroupID != nil {
+			t.Errorf("retry of %q has group %s from the rolled back batch", songs[0].Name, songs[0].GroupID)
+		}
+		return store.create(ctx, songs)
+	}
+	reader, err := bulk.NewReader(strings.NewReader("{\"name\": \"one\"}\n{\"name\": \"bad\"}\n"), models.FormatNDJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importSongs(context.Background(), reader, models.ImportOptions{BatchSize: 10}, create); err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 2 {
+		t.Fatalf("batch resolved %d groups, want 2", len(resolved))
+	}
+	if len(store.inserted) != 1 || *store.inserted[0].GroupID == resolved[0] {
+		t.Errorf("inserted %+v, want one song with a newly resolved group", store.inserted)
+	}
+}
+
+func TestImportDryRunInsertsNothing(t *testing.T) {
+	store := &fakeStore{}
+	report := importNDJSON(t, "{\"name\": \"one\"}\n{\"name\": \"\"}\n", models.ImportOptions{DryRun: true}, store)
+	if len(store.batches) != 0 {
+		t.Errorf("dry run inserted %v", store.batches)
+	}
+	if !report.DryRun || report.Rows != 2 || report.Imported != 1 || report.Failed != 1 {
+		t.Errorf("report = %+v, want 2 rows, 1 that would be imported, 1 failed", report)
+	}
+}
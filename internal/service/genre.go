@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// CreateGenre logs and calls storage.CreateGenre
+func (s *Service) CreateGenre(ctx context.Context, genre *models.Genre) error {
+	s.logger.Printf("INFO: Creating genre: %+v", genre)
+	err := s.storage.CreateGenre(ctx, genre)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create genre: %v", err)
+	}
+	return err
+}
+
+// GetGenres logs and calls storage.GetGenres
+func (s *Service) GetGenres(ctx context.Context) ([]*models.Genre, error) {
+	s.logger.Printf("INFO: Fetching genre tree")
+	genres, err := s.storage.GetGenres(ctx)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch genres: %v", err)
+	}
+	return genres, err
+}
+
+// GetGenreByID logs and calls storage.GetGenreByID
+func (s *Service) GetGenreByID(ctx context.Context, id string) (*models.Genre, error) {
+	s.logger.Printf("INFO: Fetching genre ID: %s", id)
+	genre, err := s.storage.GetGenreByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch genre ID %s: %v", id, err)
+	}
+	return genre, err
+}
+
+// UpdateGenre logs and calls storage.UpdateGenre
+func (s *Service) UpdateGenre(ctx context.Context, genre *models.Genre) error {
+	s.logger.Printf("INFO: Updating genre ID %s", genre.ID)
+	err := s.storage.UpdateGenre(ctx, genre)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update genre ID %s: %v", genre.ID, err)
+	}
+	return err
+}
+
+// DeleteGenre logs and calls storage.DeleteGenre
+func (s *Service) DeleteGenre(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting genre ID: %s", id)
+	err := s.storage.DeleteGenre(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete genre ID %s: %v", id, err)
+	}
+	return err
+}
+
+// GetSongGenres logs and calls storage.GetSongGenres
+func (s *Service) GetSongGenres(ctx context.Context, songID string) ([]models.Genre, error) {
+	s.logger.Printf("INFO: Fetching genres of song ID: %s", songID)
+	genres, err := s.storage.GetSongGenres(ctx, songID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch genres of song ID %s: %v", songID, err)
+	}
+	return genres, err
+}
+
+// SetSongGenres logs and calls storage.SetSongGenres
+func (s *Service) SetSongGenres(ctx context.Context, songID string, genreIDs []uuid.UUID) ([]models.Genre, error) {
+	s.logger.Printf("INFO: Setting %d genres on song ID %s", len(genreIDs), songID)
+	genres, err := s.storage.SetSongGenres(ctx, songID, genreIDs)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set genres on song ID %s: %v", songID, err)
+	}
+	return genres, err
+}
+
+// GetSongTags logs and calls storage.GetSongTags
+func (s *Service) GetSongTags(ctx context.Context, songID string) ([]string, error) {
+	s.logger.Printf("INFO: Fetching tags of song ID: %s", songID)
+	tags, err := s.storage.GetSongTags(ctx, songID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch tags of song ID %s: %v", songID, err)
+	}
+	return tags, err
+}
+
+// SetSongTags logs and calls storage.SetSongTags
+func (s *Service) SetSongTags(ctx context.Context, songID string, tags []string) ([]string, error) {
+	s.logger.Printf("INFO: Setting tags %v on song ID %s", tags, songID)
+	tags, err := s.storage.SetSongTags(ctx, songID, tags)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set tags on song ID %s: %v", songID, err)
+	}
+	return tags, err
+}
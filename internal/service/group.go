@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// CreateGroup logs and calls storage.CreateGroup
+func (s *Service) CreateGroup(ctx context.Context, group *models.Group) error {
+	s.logger.Printf("INFO: Creating group: %+v", group)
+	err := s.storage.CreateGroup(ctx, group)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create group: %v", err)
+	}
+	return err
+}
+
+// GetGroups logs and calls storage.GetGroups
+func (s *Service) GetGroups(ctx context.Context, limit, offset int) ([]models.Group, error) {
+	s.logger.Printf("INFO: Fetching groups with (limit: %d, offset: %d)", limit, offset)
+	groups, err := s.storage.GetGroups(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch groups: %v", err)
+	}
+	return groups, err
+}
+
+// GetGroupByID logs and calls storage.GetGroupByID
+func (s *Service) GetGroupByID(ctx context.Context, id string) (*models.Group, error) {
+	s.logger.Printf("INFO: Fetching group ID: %s", id)
+	group, err := s.storage.GetGroupByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch group ID %s: %v", id, err)
+	}
+	return group, err
+}
+
+// UpdateGroup logs and calls storage.UpdateGroup
+func (s *Service) UpdateGroup(ctx context.Context, group *models.Group) error {
+	s.logger.Printf("INFO: Updating group ID %s", group.ID)
+	err := s.storage.UpdateGroup(ctx, group)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update group ID %s: %v", group.ID, err)
+	}
+	return err
+}
+
+// DeleteGroup logs and calls storage.DeleteGroup
+func (s *Service) DeleteGroup(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting group ID: %s", id)
+	err := s.storage.DeleteGroup(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete group ID %s: %v", id, err)
+	}
+	return err
+}
+
+// GetGroupMembers logs and calls storage.GetGroupMembers
+func (s *Service) GetGroupMembers(ctx context.Context, groupID string) ([]models.GroupMember, error) {
+	s.logger.Printf("INFO: Fetching members of group ID: %s", groupID)
+	members, err := s.storage.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch members of group ID %s: %v", groupID, err)
+	}
+	return members, err
+}
+
+// AddGroupMember logs and calls storage.AddGroupMember
+func (s *Service) AddGroupMember(ctx context.Context, member *models.GroupMember) error {
+	s.logger.Printf("INFO: Adding member to group ID %s: %+v", member.GroupID, member)
+	err := s.storage.AddGroupMember(ctx, member)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to add member to group ID %s: %v", member.GroupID, err)
+	}
+	return err
+}
+
+// UpdateGroupMember logs and calls storage.UpdateGroupMember
+func (s *Service) UpdateGroupMember(ctx context.Context, member *models.GroupMember) error {
+	s.logger.Printf("INFO: Updating membership ID %s of group ID %s", member.ID, member.GroupID)
+	err := s.storage.UpdateGroupMember(ctx, member)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update membership ID %s: %v", member.ID, err)
+	}
+	return err
+}
+
+// DeleteGroupMember logs and calls storage.DeleteGroupMember
+func (s *Service) DeleteGroupMember(ctx context.Context, groupID, memberID string) error {
+	s.logger.Printf("INFO: Deleting membership ID %s of group ID %s", memberID, groupID)
+	err := s.storage.DeleteGroupMember(ctx, groupID, memberID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete membership ID %s: %v", memberID, err)
+	}
+	return err
+}
+
+// GetGroupCatalogue logs and calls storage.GetGroupCatalogue
+func (s *Service) GetGroupCatalogue(ctx context.Context, groupID string, asOf time.Time, page models.PageRequest) (*models.GroupCatalogue, error) {
+	s.logger.Printf("INFO: Fetching catalogue of group ID %s as of %s (limit: %d, sort: %q)", groupID, asOf.Format(time.DateOnly), page.Limit, page.Sort)
+	catalogue, err := s.storage.GetGroupCatalogue(ctx, groupID, asOf, page)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch catalogue of group ID %s: %v", groupID, err)
+	}
+	return catalogue, err
+}
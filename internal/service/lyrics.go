@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/lrc"
+)
+
+// GetSongLyricsSections logs and calls storage.GetSongLyricsSections
+func (s *Service) GetSongLyricsSections(ctx context.Context, id, sectionType string, expand bool) ([]models.LyricsSection, error) {
+	s.logger.Printf("INFO: Fetching lyrics sections of song ID %s (type: %q, expand: %t)", id, sectionType, expand)
+	sections, err := s.storage.GetSongLyricsSections(ctx, id, sectionType, expand)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch lyrics sections of song ID %s: %v", id, err)
+	}
+	return sections, err
+}
+
+// GetSongLyricsSection logs and calls storage.GetSongLyricsSection
+func (s *Service) GetSongLyricsSection(ctx context.Context, id string, index int, expand bool) (*models.LyricsSection, error) {
+	s.logger.Printf("INFO: Fetching lyrics section %d of song ID %s (expand: %t)", index, id, expand)
+	section, err := s.storage.GetSongLyricsSection(ctx, id, index, expand)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch lyrics section %d of song ID %s: %v", index, id, err)
+	}
+	return section, err
+}
+
+// SetSyncedLyrics parses LRC or enhanced LRC and stores it as the song's
+// synced lyrics, deriving its plain lyrics from them.
+func (s *Service) SetSyncedLyrics(ctx context.Context, id, text string, author models.Author, condition models.VersionCondition) (*models.Song, error) {
+	s.logger.Printf("INFO: Setting synced lyrics of song ID %s", id)
+	lyrics, err := lrc.Parse(text)
+	if err != nil {
+		s.logger.Printf("ERROR: Invalid LRC for song ID %s: %v", id, err)
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidLRC, err)
+	}
+	song, err := s.storage.SetSyncedLyrics(ctx, id, lyrics, author, condition)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set synced lyrics of song ID %s: %v", id, err)
+	}
+	return song, err
+}
+
+// GetSyncedLyrics returns the song's synced lyrics, parsed.
+func (s *Service) GetSyncedLyrics(ctx context.Context, id string) (*lrc.Lyrics, error) {
+	s.logger.Printf("INFO: Fetching synced lyrics of song ID %s", id)
+	synced, err := s.storage.GetSyncedLyrics(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch synced lyrics of song ID %s: %v", id, err)
+		return nil, err
+	}
+	lyrics, err := lrc.Parse(synced.LRC)
+	if err != nil {
+		s.logger.Printf("ERROR: Stored LRC of song ID %s is invalid: %v", id, err)
+	}
+	return lyrics, err
+}
+
+// GetLyricsAt finds the line and word of the song's synced lyrics sung at t.
+func (s *Service) GetLyricsAt(ctx context.Context, id string, t lrc.Timestamp) (*models.LyricsPosition, error) {
+	lyrics, err := s.GetSyncedLyrics(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return models.PositionAt(lyrics, t), nil
+}
+
+// DeleteSyncedLyrics logs and calls storage.DeleteSyncedLyrics
+func (s *Service) DeleteSyncedLyrics(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting synced lyrics of song ID %s", id)
+	err := s.storage.DeleteSyncedLyrics(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete synced lyrics of song ID %s: %v", id, err)
+	}
+	return err
+}
+
+// SetSongTranslation logs and calls storage.SetSongTranslation
+func (s *Service) SetSongTranslation(ctx context.Context, id, language string, lines []string) (*models.SongTranslation, error) {
+	s.logger.Printf("INFO: Setting %s translation of song ID %s", language, id)
+	translation, err := s.storage.SetSongTranslation(ctx, id, language, lines)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set %s translation of song ID %s: %v", language, id, err)
+	}
+	return translation, err
+}
+
+// GetSongTranslations logs and calls storage.GetSongTranslations
+func (s *Service) GetSongTranslations(ctx context.Context, id string) ([]models.SongTranslation, error) {
+	s.logger.Printf("INFO: Fetching translations of song ID %s", id)
+	translations, err := s.storage.GetSongTranslations(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch translations of song ID %s: %v", id, err)
+	}
+	return translations, err
+}
+
+// GetSongTranslation logs and calls storage.GetSongTranslation
+func (s *Service) GetSongTranslation(ctx context.Context, id, language string) (*models.SongTranslation, error) {
+	s.logger.Printf("INFO: Fetching %s translation of song ID %s", language, id)
+	translation, err := s.storage.GetSongTranslation(ctx, id, language)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch %s translation of song ID %s: %v", language, id, err)
+	}
+	return translation, err
+}
+
+// GetTranslatedLyricsPaginated logs and calls storage.GetTranslatedLyricsPaginated
+func (s *Service) GetTranslatedLyricsPaginated(ctx context.Context, id, language string, limit, offset int) ([]models.TranslatedVerse, error) {
+	s.logger.Printf("INFO: Fetching lyrics for song ID %s with %s translation (limit: %d, offset: %d)", id, language, limit, offset)
+	verses, err := s.storage.GetTranslatedLyricsPaginated(ctx, id, language, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch translated lyrics for song ID %s: %v", id, err)
+	}
+	return verses, err
+}
+
+// DeleteSongTranslation logs and calls storage.DeleteSongTranslation
+func (s *Service) DeleteSongTranslation(ctx context.Context, id, language string) error {
+	s.logger.Printf("INFO: Deleting %s translation of song ID %s", language, id)
+	err := s.storage.DeleteSongTranslation(ctx, id, language)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete %s translation of song ID %s: %v", language, id, err)
+	}
+	return err
+}
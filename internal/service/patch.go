@@ -0,0 +1,48 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/jsonpatch"
+)
+
+// PatchSong applies a JSON Merge Patch or JSON Patch, depending on patchType,
+// to the stored song. The patched song is validated before it is saved.
+func (s *Service) PatchSong(ctx context.Context, id, patchType string, patch []byte, author models.Author, condition models.VersionCondition) (*models.Song, error) {
+	s.logger.Printf("INFO: Patching song ID %s with %s", id, patchType)
+	apply := jsonpatch.Apply
+	if patchType == jsonpatch.MergePatchType {
+		apply = jsonpatch.MergePatch
+	}
+
+	song, err := s.storage.PatchSong(ctx, id, func(state models.SongState) (models.SongState, error) {
+		doc, err := json.Marshal(state)
+		if err != nil {
+			return state, err
+		}
+		doc, err = apply(doc, patch)
+		if err != nil {
+			return state, err
+		}
+
+		var patched models.SongState
+		decoder := json.NewDecoder(bytes.NewReader(doc))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&patched); err != nil {
+			return state, fmt.Errorf("%w: %v", models.ErrInvalidSong, err)
+		}
+		// A renamed group is looked up again by name unless the patch also set group_id.
+		if patched.Group != state.Group && patched.GroupID != nil && state.GroupID != nil && *patched.GroupID == *state.GroupID {
+			patched.GroupID = nil
+		}
+		return patched, patched.Validate()
+	}, author, condition)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to patch song ID %s: %v", id, err)
+	}
+	return song, err
+}
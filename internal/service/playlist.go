@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// CreatePlaylist logs and calls storage.CreatePlaylist
+func (s *Service) CreatePlaylist(ctx context.Context, playlist *models.Playlist) error {
+	s.logger.Printf("INFO: Creating playlist: %+v", playlist)
+	err := s.storage.CreatePlaylist(ctx, playlist)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create playlist: %v", err)
+	}
+	return err
+}
+
+// GetPlaylists logs and calls storage.GetPlaylists
+func (s *Service) GetPlaylists(ctx context.Context, viewerID *uuid.UUID, limit, offset int) ([]models.Playlist, error) {
+	s.logger.Printf("INFO: Fetching playlists with (limit: %d, offset: %d)", limit, offset)
+	playlists, err := s.storage.GetPlaylists(ctx, viewerID, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch playlists: %v", err)
+	}
+	return playlists, err
+}
+
+// GetPlaylistByID logs and calls storage.GetPlaylistByID
+func (s *Service) GetPlaylistByID(ctx context.Context, id string) (*models.Playlist, error) {
+	s.logger.Printf("INFO: Fetching playlist ID: %s", id)
+	playlist, err := s.storage.GetPlaylistByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch playlist ID %s: %v", id, err)
+	}
+	return playlist, err
+}
+
+// UpdatePlaylist logs and calls storage.UpdatePlaylist
+func (s *Service) UpdatePlaylist(ctx context.Context, playlist *models.Playlist) error {
+	s.logger.Printf("INFO: Updating playlist ID %s", playlist.ID)
+	err := s.storage.UpdatePlaylist(ctx, playlist)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to update playlist ID %s: %v", playlist.ID, err)
+	}
+	return err
+}
+
+// DeletePlaylist logs and calls storage.DeletePlaylist
+func (s *Service) DeletePlaylist(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Deleting playlist ID: %s", id)
+	err := s.storage.DeletePlaylist(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to delete playlist ID %s: %v", id, err)
+	}
+	return err
+}
+
+// AddPlaylistEntry logs and calls storage.AddPlaylistEntry
+func (s *Service) AddPlaylistEntry(ctx context.Context, playlistID string, input models.PlaylistEntryInput) (*models.PlaylistEntry, error) {
+	s.logger.Printf("INFO: Adding song ID %s to playlist ID %s", input.SongID, playlistID)
+	entry, err := s.storage.AddPlaylistEntry(ctx, playlistID, input)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to add song to playlist ID %s: %v", playlistID, err)
+	}
+	return entry, err
+}
+
+// MovePlaylistEntry logs and calls storage.MovePlaylistEntry
+func (s *Service) MovePlaylistEntry(ctx context.Context, playlistID, entryID string, position models.EntryPosition) (*models.PlaylistEntry, error) {
+	s.logger.Printf("INFO: Moving entry ID %s of playlist ID %s", entryID, playlistID)
+	entry, err := s.storage.MovePlaylistEntry(ctx, playlistID, entryID, position)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to move entry ID %s of playlist ID %s: %v", entryID, playlistID, err)
+	}
+	return entry, err
+}
+
+// RemovePlaylistEntry logs and calls storage.RemovePlaylistEntry
+func (s *Service) RemovePlaylistEntry(ctx context.Context, playlistID, entryID string) error {
+	s.logger.Printf("INFO: Removing entry ID %s from playlist ID %s", entryID, playlistID)
+	err := s.storage.RemovePlaylistEntry(ctx, playlistID, entryID)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to remove entry ID %s from playlist ID %s: %v", entryID, playlistID, err)
+	}
+	return err
+}
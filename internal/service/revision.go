@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// GetSongRevisions logs and calls storage.GetSongRevisions
+func (s *Service) GetSongRevisions(ctx context.Context, songID string, limit, offset int) ([]models.SongRevision, error) {
+	s.logger.Printf("INFO: Fetching revisions of song ID %s with limit: %d, offset: %d", songID, limit, offset)
+	revisions, err := s.storage.GetSongRevisions(ctx, songID, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch revisions of song ID %s: %v", songID, err)
+	}
+	return revisions, err
+}
+
+// GetSongRevision logs and calls storage.GetSongRevision
+func (s *Service) GetSongRevision(ctx context.Context, songID string, number int) (*models.SongRevision, error) {
+	s.logger.Printf("INFO: Fetching revision %d of song ID %s", number, songID)
+	revision, err := s.storage.GetSongRevision(ctx, songID, number)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch revision %d of song ID %s: %v", number, songID, err)
+	}
+	return revision, err
+}
+
+// DiffSongRevisions compares revision from with revision to, or with the
+// song's current state when to is nil.
+func (s *Service) DiffSongRevisions(ctx context.Context, songID string, from int, to *int) (*models.SongDiff, error) {
+	s.logger.Printf("INFO: Comparing revisions of song ID %s", songID)
+	base, err := s.storage.GetSongRevision(ctx, songID, from)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch revision %d of song ID %s: %v", from, songID, err)
+		return nil, err
+	}
+
+	var target models.SongState
+	if to != nil {
+		revision, err := s.storage.GetSongRevision(ctx, songID, *to)
+		if err != nil {
+			s.logger.Printf("ERROR: Failed to fetch revision %d of song ID %s: %v", *to, songID, err)
+			return nil, err
+		}
+		target = revision.State
+	} else {
+		song, err := s.storage.GetSongByID(ctx, songID)
+		if err != nil {
+			s.logger.Printf("ERROR: Failed to fetch song ID %s: %v", songID, err)
+			return nil, err
+		}
+		target = song.State()
+	}
+
+	fields, lyrics := models.DiffSongStates(base.State, target)
+	return &models.SongDiff{From: from, To: to, Fields: fields, Lyrics: lyrics}, nil
+}
+
+// RollbackSong logs and calls storage.RollbackSong
+func (s *Service) RollbackSong(ctx context.Context, songID string, number int, author models.Author) (*models.Song, error) {
+	s.logger.Printf("INFO: Rolling song ID %s back to revision %d", songID, number)
+	song, err := s.storage.RollbackSong(ctx, songID, number, author)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to roll song ID %s back to revision %d: %v", songID, number, err)
+	}
+	return song, err
+}
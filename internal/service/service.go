@@ -2,28 +2,33 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	"github.com/ruziba3vich/music_lib/internal/models"
 	"github.com/ruziba3vich/music_lib/internal/storage"
+	"github.com/ruziba3vich/music_lib/pkg/songinfo"
 )
 
 type Service struct {
-	storage *storage.Storage
-	logger  *log.Logger
+	storage  *storage.Storage
+	songInfo *songinfo.Client
+	logger   *log.Logger
 }
 
 // NewService creates a new service instance with logging
-func NewService(storage *storage.Storage, logger *log.Logger) *Service {
+func NewService(storage *storage.Storage, songInfo *songinfo.Client, logger *log.Logger) *Service {
 
 	return &Service{
-		storage: storage,
-		logger:  logger,
+		storage:  storage,
+		songInfo: songInfo,
+		logger:   logger,
 	}
 }
 
-// CreateSong logs and calls storage.CreateSong
+// CreateSong enriches the song from the external info API and calls storage.CreateSong
 func (s *Service) CreateSong(ctx context.Context, song *models.Song) error {
+	s.enrichSong(ctx, song)
 	s.logger.Printf("INFO: Creating song: %+v", song)
 	err := s.storage.CreateSong(ctx, song)
 	if err != nil {
@@ -33,9 +38,9 @@ func (s *Service) CreateSong(ctx context.Context, song *models.Song) error {
 }
 
 // DeleteSong logs and calls storage.DeleteSong
-func (s *Service) DeleteSong(ctx context.Context, id string) error {
+func (s *Service) DeleteSong(ctx context.Context, id string, condition models.VersionCondition) error {
 	s.logger.Printf("INFO: Deleting song ID: %s", id)
-	err := s.storage.DeleteSong(ctx, id)
+	err := s.storage.DeleteSong(ctx, id, condition)
 	if err != nil {
 		s.logger.Printf("ERROR: Failed to delete song ID %s: %v", id, err)
 	}
@@ -62,10 +67,10 @@ func (s *Service) GetSongLyricsPaginated(ctx context.Context, id string, limit,
 	return verses, err
 }
 
-// GetSongsWithFilters logs and calls storage.GetSongs
-func (s *Service) GetSongsWithFilters(ctx context.Context, filter map[string]any, limit, offset int) ([]models.Song, error) {
-	s.logger.Printf("INFO: Fetching songs with filter %+v (limit: %d, offset: %d)", filter, limit, offset)
-	songs, err := s.storage.GetSongsWithFilters(ctx, filter, limit, offset)
+// GetSongsWithFilters logs and calls storage.GetSongsWithFilters
+func (s *Service) GetSongsWithFilters(ctx context.Context, filter models.SongFilter, page models.PageRequest) (*models.SongPage, error) {
+	s.logger.Printf("INFO: Fetching songs with filter %+v (limit: %d, sort: %q)", filter, page.Limit, page.Sort)
+	songs, err := s.storage.GetSongsWithFilters(ctx, filter, page)
 	if err != nil {
 		s.logger.Printf("ERROR: Failed to fetch songs: %v", err)
 	}
@@ -73,29 +78,39 @@ func (s *Service) GetSongsWithFilters(ctx context.Context, filter map[string]any
 }
 
 // GetSongs logs and calls storage.GetSongs
-func (s *Service) GetSongs(ctx context.Context, limit, offset int) ([]models.Song, error) {
-	s.logger.Printf("INFO: Fetching songs with (limit: %d, offset: %d)", limit, offset)
-	songs, err := s.storage.GetSongs(ctx, limit, offset)
+func (s *Service) GetSongs(ctx context.Context, page models.PageRequest) (*models.SongPage, error) {
+	s.logger.Printf("INFO: Fetching songs with (limit: %d, sort: %q)", page.Limit, page.Sort)
+	songs, err := s.storage.GetSongs(ctx, page)
 	if err != nil {
 		s.logger.Printf("ERROR: Failed to fetch songs: %v", err)
 	}
 	return songs, err
 }
 
+// GetSongListingState calls storage.GetSongListingState. It runs on every
+// listing request, so only failures are logged.
+func (s *Service) GetSongListingState(ctx context.Context, filter models.SongFilter) (*models.ListingState, error) {
+	state, err := s.storage.GetSongListingState(ctx, filter)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to summarize songs with filter %+v: %v", filter, err)
+	}
+	return state, err
+}
+
 // UpdateSong logs and calls storage.UpdateSong
-func (s *Service) UpdateSong(ctx context.Context, song *models.Song) error {
+func (s *Service) UpdateSong(ctx context.Context, song *models.Song, author models.Author, condition models.VersionCondition) error {
 	s.logger.Printf("INFO: Updating song ID %s", song.ID)
-	err := s.storage.UpdateSong(ctx, song)
+	err := s.storage.UpdateSong(ctx, song, author, condition)
 	if err != nil {
 		s.logger.Printf("ERROR: Failed to update song ID %s: %v", song.ID, err)
 	}
 	return err
 }
 
-func (s *Service) GetSongsByArtist(ctx context.Context, artist string, limit, offset int) ([]models.Song, error) {
-	s.logger.Printf("INFO: Searching for songs by artist: %s, limit: %d, offset: %d", artist, limit, offset)
+func (s *Service) GetSongsByArtist(ctx context.Context, artist string, page models.PageRequest) (*models.SongPage, error) {
+	s.logger.Printf("INFO: Searching for songs by artist: %s, limit: %d, sort: %q", artist, page.Limit, page.Sort)
 
-	songs, err := s.storage.GetSongsByArtist(ctx, artist, limit, offset)
+	songs, err := s.storage.GetSongsByArtist(ctx, artist, page)
 	if err != nil {
 		s.logger.Printf("ERROR: Failed to fetch songs for artist %s: %v", artist, err)
 		return nil, err
@@ -103,3 +118,63 @@ func (s *Service) GetSongsByArtist(ctx context.Context, artist string, limit, of
 
 	return songs, nil
 }
+
+// SearchSongs logs and calls storage.SearchSongs
+func (s *Service) SearchSongs(ctx context.Context, query, language string, limit, offset int) ([]models.SearchResult, error) {
+	s.logger.Printf("INFO: Searching songs for %q (language: %q, limit: %d, offset: %d)", query, language, limit, offset)
+	results, err := s.storage.SearchSongs(ctx, query, language, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to search songs for %q: %v", query, err)
+	}
+	return results, err
+}
+
+// FuzzySearchSongs logs and calls storage.FuzzySearchSongs
+func (s *Service) FuzzySearchSongs(ctx context.Context, query, language string, threshold float64, limit, offset int) ([]models.SearchResult, error) {
+	s.logger.Printf("INFO: Fuzzy searching songs for %q (language: %q, threshold: %.2f, limit: %d, offset: %d)", query, language, threshold, limit, offset)
+	results, err := s.storage.FuzzySearchSongs(ctx, query, language, threshold, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fuzzy search songs for %q: %v", query, err)
+	}
+	return results, err
+}
+
+// DetectSongLanguages logs and calls storage.DetectSongLanguages
+func (s *Service) DetectSongLanguages(ctx context.Context, batchSize int) (int, error) {
+	s.logger.Printf("INFO: Detecting song languages (batch size: %d)", batchSize)
+	changed, err := s.storage.DetectSongLanguages(ctx, batchSize)
+	if err != nil {
+		s.logger.Printf("ERROR: Language detection stopped after %d changes: %v", changed, err)
+		return changed, err
+	}
+	s.logger.Printf("INFO: Updated the language of %d songs", changed)
+	return changed, nil
+}
+
+// enrichSong fills in the release date, lyrics and link the client left out using the
+// external info API. Failures are logged and the song is kept as submitted.
+func (s *Service) enrichSong(ctx context.Context, song *models.Song) {
+	if s.songInfo == nil || (song.Lyrics != "" && !song.ReleaseDate.IsZero() && song.Link != "") {
+		return
+	}
+
+	detail, err := s.songInfo.GetSongDetail(ctx, song.Group, song.Name)
+	if errors.Is(err, songinfo.ErrNotFound) {
+		s.logger.Printf("INFO: No external details for song %q by %q", song.Name, song.Group)
+		return
+	}
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch external details for song %q by %q: %v", song.Name, song.Group, err)
+		return
+	}
+
+	if song.ReleaseDate.IsZero() {
+		song.ReleaseDate = detail.ReleaseDate
+	}
+	if song.Lyrics == "" {
+		song.Lyrics = detail.Text
+	}
+	if song.Link == "" {
+		song.Link = detail.Link
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// GetTrash logs and calls storage.GetTrash
+func (s *Service) GetTrash(ctx context.Context, limit, offset int) ([]models.Song, error) {
+	s.logger.Printf("INFO: Fetching trash with limit: %d, offset: %d", limit, offset)
+	songs, err := s.storage.GetTrash(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch trash: %v", err)
+	}
+	return songs, err
+}
+
+// RestoreSong logs and calls storage.RestoreSong
+func (s *Service) RestoreSong(ctx context.Context, id string) (*models.Song, error) {
+	s.logger.Printf("INFO: Restoring song ID %s", id)
+	song, err := s.storage.RestoreSong(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to restore song ID %s: %v", id, err)
+	}
+	return song, err
+}
+
+// PurgeSong logs and calls storage.PurgeSong
+func (s *Service) PurgeSong(ctx context.Context, id string) error {
+	s.logger.Printf("INFO: Purging song ID %s", id)
+	err := s.storage.PurgeSong(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to purge song ID %s: %v", id, err)
+	}
+	return err
+}
+
+// RunTrashPurger permanently deletes songs that have been in the trash longer
+// than retention, once every interval, until ctx is cancelled.
+func (s *Service) RunTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := s.storage.PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			s.logger.Printf("ERROR: Failed to purge trash: %v", err)
+		} else if purged > 0 {
+			s.logger.Printf("INFO: Purged %d songs from the trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
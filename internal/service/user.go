@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// dummyHash is compared against when a username does not exist, so failed
+// logins take as long for unknown users as for wrong passwords.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+// Register creates a viewer account with the given credentials
+func (s *Service) Register(ctx context.Context, credentials models.Credentials) (*models.User, error) {
+	s.logger.Printf("INFO: Registering user: %s", credentials.Username)
+	user, err := s.createUser(ctx, credentials, models.RoleViewer)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to register user: %v", err)
+	}
+	return user, err
+}
+
+// CreateAdmin creates an admin account with the given credentials. It is for
+// operators setting up a deployment, since registration only makes viewers.
+func (s *Service) CreateAdmin(ctx context.Context, credentials models.Credentials) (*models.User, error) {
+	s.logger.Printf("INFO: Creating admin: %s", credentials.Username)
+	user, err := s.createUser(ctx, credentials, models.RoleAdmin)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to create admin: %v", err)
+	}
+	return user, err
+}
+
+// BootstrapAdmin creates the configured admin account on startup unless the
+// username is already taken. An existing account is never promoted, since
+// anyone may have registered the name before it was configured.
+func (s *Service) BootstrapAdmin(ctx context.Context, credentials models.Credentials) error {
+	_, err := s.CreateAdmin(ctx, credentials)
+	if !errors.Is(err, models.ErrDuplicateUser) {
+		return err
+	}
+	user, err := s.storage.GetUserByUsername(ctx, credentials.Username)
+	if err != nil {
+		return err
+	}
+	if user.Role != models.RoleAdmin {
+		return fmt.Errorf("admin %q: %w by an account that is not an admin", user.Username, models.ErrDuplicateUser)
+	}
+	s.logger.Printf("INFO: Admin %s already exists", user.Username)
+	return nil
+}
+
+// createUser validates the credentials, hashes the password with bcrypt and
+// calls storage.CreateUser
+func (s *Service) createUser(ctx context.Context, credentials models.Credentials, role string) (*models.User, error) {
+	if err := credentials.Validate(); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user := &models.User{
+		ID:           uuid.New(),
+		Username:     credentials.Username,
+		PasswordHash: string(hash),
+		Role:         role,
+	}
+	if err := s.storage.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Authenticate checks the credentials against the stored bcrypt hash
+func (s *Service) Authenticate(ctx context.Context, credentials models.Credentials) (*models.User, error) {
+	s.logger.Printf("INFO: Authenticating user: %s", credentials.Username)
+	user, err := s.storage.GetUserByUsername(ctx, credentials.Username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(credentials.Password))
+		s.logger.Printf("ERROR: Failed to authenticate user %s: unknown username", credentials.Username)
+		return nil, models.ErrBadCredentials
+	}
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch user %s: %v", credentials.Username, err)
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)); err != nil {
+		s.logger.Printf("ERROR: Failed to authenticate user %s: wrong password", credentials.Username)
+		return nil, models.ErrBadCredentials
+	}
+	return user, nil
+}
+
+// GetUserByID logs and calls storage.GetUserByID
+func (s *Service) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	s.logger.Printf("INFO: Fetching user ID: %s", id)
+	user, err := s.storage.GetUserByID(ctx, id)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch user ID %s: %v", id, err)
+	}
+	return user, err
+}
+
+// GetUsers logs and calls storage.GetUsers
+func (s *Service) GetUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
+	s.logger.Printf("INFO: Fetching users with (limit: %d, offset: %d)", limit, offset)
+	users, err := s.storage.GetUsers(ctx, limit, offset)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to fetch users: %v", err)
+	}
+	return users, err
+}
+
+// SetUserRole logs and calls storage.SetUserRole
+func (s *Service) SetUserRole(ctx context.Context, id, role string) (*models.User, error) {
+	s.logger.Printf("INFO: Setting role of user ID %s to %s", id, role)
+	user, err := s.storage.SetUserRole(ctx, id, role)
+	if err != nil {
+		s.logger.Printf("ERROR: Failed to set role of user ID %s: %v", id, err)
+	}
+	return user, err
+}
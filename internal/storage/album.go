@@ -0,0 +1,363 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) CreateAlbum(ctx context.Context, album *models.Album) error {
+	return s.db.Omit(clause.Associations).Create(album).Error
+}
+
+func (s *Storage) GetAlbums(ctx context.Context, limit, offset int) ([]models.Album, error) {
+	albums := []models.Album{}
+	if err := s.db.Order("title, id").Limit(limit).Offset(offset).Find(&albums).Error; err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+// GetAlbumByID returns the album with its releases, oldest first.
+func (s *Storage) GetAlbumByID(ctx context.Context, id string) (*models.Album, error) {
+	albumUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var album models.Album
+	err = s.db.Preload("Releases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("release_date NULLS LAST, id")
+	}).Where("id = ?", albumUUID).First(&album).Error
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+func (s *Storage) UpdateAlbum(ctx context.Context, album *models.Album) error {
+	return s.updateByID(&models.Album{}, album.ID, album, "title", "group_id")
+}
+
+// DeleteAlbum removes the album; its releases are kept without an album.
+func (s *Storage) DeleteAlbum(ctx context.Context, id string) error {
+	return s.deleteByID(&models.Album{}, id)
+}
+
+func (s *Storage) CreateRelease(ctx context.Context, release *models.Release) error {
+	return s.db.Omit(clause.Associations).Create(release).Error
+}
+
+func (s *Storage) GetReleases(ctx context.Context, limit, offset int) ([]models.Release, error) {
+	releases := []models.Release{}
+	err := s.db.Order("release_date DESC NULLS LAST, id").Limit(limit).Offset(offset).Find(&releases).Error
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// GetReleaseByID returns the release with its tracklist in playing order.
+func (s *Storage) GetReleaseByID(ctx context.Context, id string) (*models.Release, error) {
+	releaseUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return getRelease(s.db, releaseUUID)
+}
+
+// UpdateRelease changes the release's details, which songs on it show as well.
+func (s *Storage) UpdateRelease(ctx context.Context, release *models.Release) error {
+	var songIDs []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Release{}).Where("id = ?", release.ID).
+			Select("album_id", "title", "release_date", "label", "format").
+			Updates(release)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var err error
+		songIDs, err = touchSongs(tx, songIDsByRelease(tx, release.ID))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return s.invalidateSongs(ctx, songIDs)
+}
+
+// DeleteRelease removes the release and its tracklist.
+func (s *Storage) DeleteRelease(ctx context.Context, id string) error {
+	releaseUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	var songIDs []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if songIDs, err = touchSongs(tx, songIDsByRelease(tx, releaseUUID)); err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", releaseUUID).Delete(&models.Release{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.invalidateSongs(ctx, songIDs)
+}
+
+// SetReleaseTracks replaces the tracklist. The order of inputs is the playing
+// order within each disc; their track numbers are ignored.
+func (s *Storage) SetReleaseTracks(ctx context.Context, releaseID string, inputs []models.TrackInput) (*models.Release, error) {
+	return s.editTracklist(ctx, releaseID, func(discs map[int][]models.Track) error {
+		clear(discs)
+		for _, input := range inputs {
+			disc := max(input.DiscNumber, 1)
+			discs[disc] = append(discs[disc], models.Track{ID: uuid.New(), SongID: input.SongID})
+		}
+		return nil
+	})
+}
+
+// AddReleaseTrack inserts a song at the given position, shifting the following tracks down.
+func (s *Storage) AddReleaseTrack(ctx context.Context, releaseID string, input models.TrackInput) (*models.Release, error) {
+	return s.editTracklist(ctx, releaseID, func(discs map[int][]models.Track) error {
+		disc := max(input.DiscNumber, 1)
+		discs[disc] = insertTrack(discs[disc], input.TrackNumber, models.Track{ID: uuid.New(), SongID: input.SongID})
+		return nil
+	})
+}
+
+// MoveReleaseTrack moves a track to another position, possibly on another disc.
+func (s *Storage) MoveReleaseTrack(ctx context.Context, releaseID, trackID string, position models.TrackPosition) (*models.Release, error) {
+	return s.editTracklist(ctx, releaseID, func(discs map[int][]models.Track) error {
+		track, err := removeTrack(discs, trackID)
+		if err != nil {
+			return err
+		}
+		disc := max(position.DiscNumber, 1)
+		discs[disc] = insertTrack(discs[disc], position.TrackNumber, track)
+		return nil
+	})
+}
+
+// RemoveReleaseTrack removes a track and closes the gap it leaves.
+func (s *Storage) RemoveReleaseTrack(ctx context.Context, releaseID, trackID string) (*models.Release, error) {
+	return s.editTracklist(ctx, releaseID, func(discs map[int][]models.Track) error {
+		_, err := removeTrack(discs, trackID)
+		return err
+	})
+}
+
+// GetSongReleases lists the releases a song appears on, oldest first.
+func (s *Storage) GetSongReleases(ctx context.Context, songID string) ([]models.SongAppearance, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []models.Track
+	err = s.db.Joins("JOIN releases ON releases.id = tracks.release_id").
+		Where("tracks.song_id = ?", songUUID).
+		Order("releases.release_date NULLS LAST, releases.id, tracks.disc_number, tracks.track_number").
+		Find(&tracks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	appearances := make([]models.SongAppearance, 0, len(tracks))
+	if len(tracks) == 0 {
+		return appearances, nil
+	}
+	releaseIDs := make([]uuid.UUID, 0, len(tracks))
+	for _, track := range tracks {
+		releaseIDs = append(releaseIDs, track.ReleaseID)
+	}
+	var releases []models.Release
+	if err := s.db.Where("id IN ?", releaseIDs).Find(&releases).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.Release, len(releases))
+	for _, release := range releases {
+		byID[release.ID] = release
+	}
+
+	for _, track := range tracks {
+		appearances = append(appearances, models.SongAppearance{
+			Release:     byID[track.ReleaseID],
+			DiscNumber:  track.DiscNumber,
+			TrackNumber: track.TrackNumber,
+		})
+	}
+	return appearances, nil
+}
+
+// editTracklist loads the release's tracks grouped by disc, lets edit change
+// them, then renumbers every disc from 1 and writes the tracklist back.
+func (s *Storage) editTracklist(ctx context.Context, releaseID string, edit func(discs map[int][]models.Track) error) (*models.Release, error) {
+	releaseUUID, err := uuid.Parse(releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var release *models.Release
+	var songIDs []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Release
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", releaseUUID).First(&locked).Error; err != nil {
+			return err
+		}
+
+		var tracks []models.Track
+		if err := tx.Where("release_id = ?", releaseUUID).Order("disc_number, track_number").Find(&tracks).Error; err != nil {
+			return err
+		}
+		discs := make(map[int][]models.Track)
+		for _, track := range tracks {
+			discs[track.DiscNumber] = append(discs[track.DiscNumber], track)
+		}
+
+		if err := edit(discs); err != nil {
+			return err
+		}
+
+		if err := tx.Where("release_id = ?", releaseUUID).Delete(&models.Track{}).Error; err != nil {
+			return err
+		}
+		affected := make([]uuid.UUID, 0, len(tracks))
+		for _, track := range tracks {
+			affected = append(affected, track.SongID)
+		}
+		if tracks := numberTracks(releaseUUID, discs); len(tracks) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&tracks).Error; err != nil {
+				return err
+			}
+			for _, track := range tracks {
+				affected = append(affected, track.SongID)
+			}
+		}
+		if len(affected) > 0 {
+			var err error
+			if songIDs, err = touchSongs(tx, affected); err != nil {
+				return err
+			}
+		}
+
+		updated, err := getRelease(tx, releaseUUID)
+		release = updated
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invalidateSongs(ctx, songIDs); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// numberTracks flattens the discs into a tracklist numbered from 1 on every
+// disc. Empty discs are dropped and the remaining ones renumbered from 1.
+func numberTracks(releaseID uuid.UUID, discs map[int][]models.Track) []models.Track {
+	discNumbers := make([]int, 0, len(discs))
+	for disc, tracks := range discs {
+		if len(tracks) > 0 {
+			discNumbers = append(discNumbers, disc)
+		}
+	}
+	sort.Ints(discNumbers)
+
+	var result []models.Track
+	for i, disc := range discNumbers {
+		for j, track := range discs[disc] {
+			track.ReleaseID = releaseID
+			track.DiscNumber = i + 1
+			track.TrackNumber = j + 1
+			track.Song = nil
+			result = append(result, track)
+		}
+	}
+	return result
+}
+
+// insertTrack puts track at the 1-based position, or at the end when the position is 0 or past the end.
+func insertTrack(tracks []models.Track, position int, track models.Track) []models.Track {
+	if position <= 0 || position > len(tracks) {
+		return append(tracks, track)
+	}
+	tracks = append(tracks, models.Track{})
+	copy(tracks[position:], tracks[position-1:])
+	tracks[position-1] = track
+	return tracks
+}
+
+func removeTrack(discs map[int][]models.Track, trackID string) (models.Track, error) {
+	for disc, tracks := range discs {
+		for i, track := range tracks {
+			if track.ID.String() == trackID {
+				discs[disc] = append(tracks[:i:i], tracks[i+1:]...)
+				return track, nil
+			}
+		}
+	}
+	return models.Track{}, gorm.ErrRecordNotFound
+}
+
+func getRelease(db *gorm.DB, id uuid.UUID) (*models.Release, error) {
+	var release models.Release
+	err := db.Preload("Tracks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("disc_number, track_number")
+	}).Preload("Tracks.Song").Where("id = ?", id).First(&release).Error
+	if err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// songIDsByRelease selects the songs on a release's tracklist.
+func songIDsByRelease(db *gorm.DB, releaseID uuid.UUID) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("tracks").
+		Select("song_id").
+		Where("release_id = ?", releaseID)
+}
+
+// updateByID updates the given columns of the row with the given primary key.
+func (s *Storage) updateByID(model any, id uuid.UUID, values any, columns ...string) error {
+	result := s.db.Model(model).Where("id = ?", id).Select(columns).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *Storage) deleteByID(model any, id string) error {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	result := s.db.Where("id = ?", uid).Delete(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
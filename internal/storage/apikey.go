@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval limits how often a key's last-used timestamp is written.
+const apiKeyTouchInterval = time.Minute
+
+func (s *Storage) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return s.db.Create(key).Error
+}
+
+// GetAPIKeys lists keys, newest first, including revoked and expired ones.
+func (s *Storage) GetAPIKeys(ctx context.Context, limit, offset int) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	if err := s.db.Order("created_at DESC, id").Limit(limit).Offset(offset).Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *Storage) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := s.db.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey marks the key revoked. Revoking an already revoked key keeps the original time.
+func (s *Storage) RevokeAPIKey(ctx context.Context, id string) (*models.APIKey, error) {
+	keyUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var key models.APIKey
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", keyUUID).First(&key).Error; err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+		now := time.Now()
+		key.RevokedAt = &now
+		return tx.Model(&key).Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchAPIKey records that the key was used, at most once per apiKeyTouchInterval.
+func (s *Storage) TouchAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return s.db.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-apiKeyTouchInterval)).
+		Update("last_used_at", at).Error
+}
@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) CreateArtist(ctx context.Context, artist *models.Artist) error {
+	artist.Name = models.CleanName(artist.Name)
+	artist.NormalizedName = models.NormalizeName(artist.Name)
+	err := s.db.Create(artist).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.ErrDuplicateArtist
+	}
+	return err
+}
+
+func (s *Storage) GetArtists(ctx context.Context, limit, offset int) ([]models.Artist, error) {
+	artists := []models.Artist{}
+	if err := s.db.Order("normalized_name, id").Limit(limit).Offset(offset).Find(&artists).Error; err != nil {
+		return nil, err
+	}
+	return artists, nil
+}
+
+func (s *Storage) GetArtistByID(ctx context.Context, id string) (*models.Artist, error) {
+	artistUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var artist models.Artist
+	if err := s.db.Where("id = ?", artistUUID).First(&artist).Error; err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// UpdateArtist saves the artist and renames it in the artists array of every song it is credited on.
+func (s *Storage) UpdateArtist(ctx context.Context, artist *models.Artist) error {
+	artist.Name = models.CleanName(artist.Name)
+	artist.NormalizedName = models.NormalizeName(artist.Name)
+
+	var songIDs []string
+	var renamed []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Artist
+		if err := tx.Where("id = ?", artist.ID).First(&current).Error; err != nil {
+			return err
+		}
+		artist.CreatedAt = current.CreatedAt
+
+		if err := tx.Save(artist).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return models.ErrDuplicateArtist
+			}
+			return err
+		}
+		if current.Name == artist.Name {
+			return nil
+		}
+		renamed = []string{artistTag(current.Name)}
+
+		if err := tx.Model(&models.SongArtist{}).Where("artist_id = ?", artist.ID).Pluck("song_id", &songIDs).Error; err != nil {
+			return err
+		}
+		if len(songIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Song{}).
+			Where("id IN ?", songIDs).
+			Updates(map[string]any{
+				"artists": gorm.Expr("array_replace(artists, ?, ?)", current.Name, artist.Name),
+				"version": gorm.Expr("version + 1"),
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+	// Listings by the old name no longer include the songs.
+	return s.invalidateSongs(ctx, songIDs, renamed...)
+}
+
+// DeleteArtist removes an artist that is not credited on any song.
+func (s *Storage) DeleteArtist(ctx context.Context, id string) error {
+	artistUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var credits int64
+		if err := tx.Model(&models.SongArtist{}).Where("artist_id = ?", artistUUID).Count(&credits).Error; err != nil {
+			return err
+		}
+		if credits > 0 {
+			return models.ErrArtistInUse
+		}
+		result := tx.Where("id = ?", artistUUID).Delete(&models.Artist{})
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return models.ErrArtistInUse
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (s *Storage) GetSongsByArtistID(ctx context.Context, id string, page models.PageRequest) (*models.SongPage, error) {
+	artistUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.songPageWithFacets(func() *gorm.DB {
+		return s.db.Where("is_deleted = false AND id IN (?)",
+			s.db.Model(&models.SongArtist{}).Select("song_id").Where("artist_id = ?", artistUUID))
+	}, page)
+}
+
+// GetSongArtists returns the song's credits in order.
+func (s *Storage) GetSongArtists(ctx context.Context, songID string) ([]models.SongArtist, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	credits := []models.SongArtist{}
+	err = s.db.Preload("Artist").Where("song_id = ?", songUUID).Order("position").Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// SetSongArtists replaces the song's credits and rewrites its artists array to match.
+func (s *Storage) SetSongArtists(ctx context.Context, songID string, inputs []models.SongArtistInput) ([]models.SongArtist, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var song models.Song
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_deleted = false", songUUID).First(&song).Error; err != nil {
+			return err
+		}
+		previous = songTags(&song)
+
+		credits := make([]models.SongArtist, 0, len(inputs))
+		names := make([]string, 0, len(inputs))
+		for _, input := range inputs {
+			var artist models.Artist
+			if err := tx.Where("id = ?", input.ArtistID).First(&artist).Error; err != nil {
+				return err
+			}
+			credits = append(credits, models.SongArtist{
+				SongID:   songUUID,
+				ArtistID: artist.ID,
+				Position: len(credits),
+				Role:     input.Role,
+			})
+			names = append(names, artist.Name)
+		}
+
+		if err := replaceSongArtists(tx, songUUID, credits); err != nil {
+			return err
+		}
+		return tx.Model(&models.Song{}).Where("id = ?", songUUID).
+			Updates(map[string]any{"artists": pq.StringArray(names), "version": gorm.Expr("version + 1")}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invalidateSongs(ctx, []string{songID}, previous...); err != nil {
+		return nil, err
+	}
+	return s.GetSongArtists(ctx, songID)
+}
+
+// syncSongArtists resolves the names in song.Artists to artist rows, creating
+// missing ones, and rewrites the song's credits to match. Names are replaced
+// with the artists' canonical spelling and roles of existing credits are kept.
+func syncSongArtists(tx *gorm.DB, song *models.Song) error {
+	var existing []models.SongArtist
+	if err := tx.Where("song_id = ?", song.ID).Find(&existing).Error; err != nil {
+		return err
+	}
+	roles := make(map[uuid.UUID]string, len(existing))
+	for _, credit := range existing {
+		roles[credit.ArtistID] = credit.Role
+	}
+
+	credits := make([]models.SongArtist, 0, len(song.Artists))
+	names := make([]string, 0, len(song.Artists))
+	seen := make(map[uuid.UUID]bool, len(song.Artists))
+	for _, name := range song.Artists {
+		if models.NormalizeName(name) == "" {
+			continue
+		}
+		artist, err := findOrCreateArtist(tx, name)
+		if err != nil {
+			return err
+		}
+		if seen[artist.ID] {
+			continue
+		}
+		seen[artist.ID] = true
+
+		role := roles[artist.ID]
+		if role == "" {
+			role = models.RolePerformer
+		}
+		credits = append(credits, models.SongArtist{
+			SongID:   song.ID,
+			ArtistID: artist.ID,
+			Position: len(credits),
+			Role:     role,
+		})
+		names = append(names, artist.Name)
+	}
+
+	if err := replaceSongArtists(tx, song.ID, credits); err != nil {
+		return err
+	}
+	song.Artists = pq.StringArray(names)
+	return tx.Model(&models.Song{}).Where("id = ?", song.ID).Update("artists", song.Artists).Error
+}
+
+// songIDsByArtistName is a subquery selecting the songs credited to the named artist,
+// whatever its spelling.
+func songIDsByArtistName(db *gorm.DB, name string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("song_artists").
+		Select("song_artists.song_id").
+		Joins("JOIN artists ON artists.id = song_artists.artist_id").
+		Where("artists.normalized_name = ?", models.NormalizeName(name))
+}
+
+func replaceSongArtists(tx *gorm.DB, songID uuid.UUID, credits []models.SongArtist) error {
+	if err := tx.Where("song_id = ?", songID).Delete(&models.SongArtist{}).Error; err != nil {
+		return err
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+	return tx.Omit("Artist").Create(&credits).Error
+}
+
+func findOrCreateArtist(tx *gorm.DB, name string) (*models.Artist, error) {
+	candidate := models.Artist{
+		ID:             uuid.New(),
+		Name:           models.CleanName(name),
+		NormalizedName: models.NormalizeName(name),
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "normalized_name"}},
+		DoNothing: true,
+	}).Create(&candidate).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var artist models.Artist
+	if err := tx.Where("normalized_name = ?", candidate.NormalizedName).First(&artist).Error; err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+// CreateSongs inserts songs in a single transaction, so either all of them are
+// created or none. Bulk-created songs are not cached up front, but the cached
+// listings they belong in are dropped.
+func (s *Storage) CreateSongs(ctx context.Context, songs []models.Song) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range songs {
+			if err := resolveSongGroup(tx, &songs[i]); err != nil {
+				return err
+			}
+			songs[i].DetectLanguage()
+			if err := tx.Create(&songs[i]).Error; err != nil {
+				return err
+			}
+			if err := syncSongArtists(tx, &songs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var tags []string
+	for i := range songs {
+		tags = append(tags, songTags(&songs[i])...)
+	}
+	return s.invalidateSongs(ctx, nil, tags...)
+}
+
+// ExportSongs calls fn for every song matching the filter, oldest first. Songs
+// are read from a database cursor one at a time, so the result set is never
+// held in memory; the song passed to fn is reused between calls. Without
+// lyrics, the lyrics column is not read at all.
+func (s *Storage) ExportSongs(ctx context.Context, filter models.SongFilter, lyrics bool, fn func(*models.Song) error) error {
+	query := applySongFilter(s.db.WithContext(ctx), filter).Model(&models.Song{}).Order("created_at, id")
+	if !lyrics {
+		query = query.Omit("lyrics")
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var song models.Song
+	for rows.Next() {
+		song = models.Song{}
+		if err := s.db.ScanRows(rows, &song); err != nil {
+			return err
+		}
+		if err := fn(&song); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
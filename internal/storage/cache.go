@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+// listTag tags cached listings that are not narrowed to one artist or group,
+// which any change to any song can affect.
+const listTag = "list"
+
+func artistTag(name string) string {
+	return "artist:" + models.NormalizeName(name)
+}
+
+func groupTag(id uuid.UUID) string {
+	return "group:" + id.String()
+}
+
+// songTags returns the tags of the cached listings the songs appear in, which
+// a change to them affects. Callers pass a song both as it was and as it is
+// when a change can move it to another artist or group.
+func songTags(songs ...*models.Song) []string {
+	tags := []string{listTag}
+	for _, song := range songs {
+		for _, artist := range song.Artists {
+			tags = append(tags, artistTag(artist))
+		}
+		if song.GroupID != nil {
+			tags = append(tags, groupTag(*song.GroupID))
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// filterTags returns the tag of a filtered listing: its group or artist when
+// it is narrowed to one, since only changes to their songs can affect it.
+func filterTags(filter models.SongFilter) []string {
+	switch {
+	case filter.GroupID != nil:
+		return []string{groupTag(*filter.GroupID)}
+	case filter.Artist != "":
+		return []string{artistTag(filter.Artist)}
+	default:
+		return []string{listTag}
+	}
+}
+
+// cachedPageQuery identifies a cached listing. Fields are normalized the way
+// the database compares them, so equivalent requests share a cache entry.
+type cachedPageQuery struct {
+	Listing string             `json:"l"`
+	Filter  *models.SongFilter `json:"f,omitempty"`
+	Artist  string             `json:"a,omitempty"`
+	Limit   int                `json:"n"`
+	Sort    string             `json:"s,omitempty"`
+	Cursor  string             `json:"c,omitempty"`
+}
+
+func newCachedPageQuery(listing string, page models.PageRequest) cachedPageQuery {
+	query := cachedPageQuery{Listing: listing, Limit: pageLimit(page.Limit), Sort: page.Sort, Cursor: page.Cursor}
+	if query.Sort == "" && query.Cursor == "" {
+		query.Sort = defaultSort
+	}
+	return query
+}
+
+// normalizeFilter rewrites the filter in the form the database matches it in.
+func normalizeFilter(filter models.SongFilter) models.SongFilter {
+	if filter.Artist != "" {
+		filter.Artist = models.NormalizeName(filter.Artist)
+	}
+	if filter.Genre != "" {
+		filter.Genre = models.GenreSlug(filter.Genre)
+	}
+	if len(filter.Tags) > 0 {
+		tags := make([]string, 0, len(filter.Tags))
+		for _, tag := range filter.Tags {
+			tags = append(tags, models.NormalizeName(tag))
+		}
+		slices.Sort(tags)
+		filter.Tags = slices.Compact(tags)
+	}
+	for _, t := range []**time.Time{&filter.ReleasedFrom, &filter.ReleasedTo, &filter.CreatedFrom, &filter.CreatedTo} {
+		if *t != nil {
+			utc := (*t).UTC()
+			*t = &utc
+		}
+	}
+	return filter
+}
+
+// cachedSongPage returns the page from the cache, or loads it and caches it.
+// The cache is best effort: when Redis fails, the page is read from the database.
+func (s *Storage) cachedSongPage(ctx context.Context, query cachedPageQuery, tags []string, load func() (*models.SongPage, error)) (*models.SongPage, error) {
+	data, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.redisservice.PageKey(ctx, string(data), tags)
+	if err != nil {
+		return load()
+	}
+	if page, err := s.redisservice.GetSongPage(ctx, key); err == nil && page != nil {
+		return page, nil
+	}
+
+	page, err := load()
+	if err != nil {
+		return nil, err
+	}
+	_ = s.redisservice.AddSongPage(ctx, key, page)
+	return page, nil
+}
+
+// cacheSong caches the song as written and drops the cached listings with the
+// given tags, which the write affected.
+func (s *Storage) cacheSong(ctx context.Context, song *models.Song, tags []string) error {
+	if err := s.redisservice.AddSong(ctx, song); err != nil {
+		return err
+	}
+	return s.redisservice.InvalidatePages(ctx, tags...)
+}
+
+// invalidateSongs drops the cached copies of the songs and the cached listings
+// they appear in, along with the listings of any extra tags, such as the old
+// name of a renamed artist.
+func (s *Storage) invalidateSongs(ctx context.Context, songIDs []string, tags ...string) error {
+	if len(songIDs) == 0 && len(tags) == 0 {
+		return nil
+	}
+	if len(songIDs) > 0 {
+		var songs []models.Song
+		if err := s.db.Select("id", "artists", "group_id").Where("id IN ?", songIDs).Find(&songs).Error; err != nil {
+			return err
+		}
+		for i := range songs {
+			tags = append(tags, songTags(&songs[i])...)
+		}
+		if err := s.redisservice.InvalidateSongs(ctx, songIDs...); err != nil {
+			return err
+		}
+	}
+	slices.Sort(tags)
+	return s.redisservice.InvalidatePages(ctx, slices.Compact(tags)...)
+}
+
+// GetSongListingState summarizes the songs matching filter, for validating a
+// client's copy of a listing. It is cached under the same tags as the
+// listing's pages, so it changes whenever a cached page would.
+func (s *Storage) GetSongListingState(ctx context.Context, filter models.SongFilter) (*models.ListingState, error) {
+	filter = normalizeFilter(filter)
+	data, err := json.Marshal(cachedPageQuery{Listing: "state", Filter: &filter})
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.redisservice.PageKey(ctx, string(data), filterTags(filter))
+	if err != nil {
+		return s.songListingState(filter)
+	}
+	if state, err := s.redisservice.GetListingState(ctx, key); err == nil && state != nil {
+		return state, nil
+	}
+
+	state, err := s.songListingState(filter)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.redisservice.AddListingState(ctx, key, state)
+	return state, nil
+}
+
+func (s *Storage) songListingState(filter models.SongFilter) (*models.ListingState, error) {
+	var state models.ListingState
+	var updatedAt *time.Time
+	row := applySongFilter(s.db, filter).Model(&models.Song{}).
+		Select("count(*), coalesce(sum(version), 0), max(updated_at)").Row()
+	if err := row.Scan(&state.Count, &state.Versions, &updatedAt); err != nil {
+		return nil, err
+	}
+	if updatedAt != nil {
+		state.UpdatedAt = *updatedAt
+	}
+
+	var lastModified *time.Time
+	if err := s.db.Model(&models.Song{}).Select("max(updated_at)").Row().Scan(&lastModified); err != nil {
+		return nil, err
+	}
+	if lastModified != nil {
+		state.LastModified = *lastModified
+	}
+	return &state, nil
+}
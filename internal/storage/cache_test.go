@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+func TestFilterTags(t *testing.T) {
+	groupID := uuid.MustParse("7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23")
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter models.SongFilter
+		want   []string
+	}{
+		{name: "group", filter: models.SongFilter{GroupID: &groupID}, want: []string{groupTag(groupID)}},
+		{name: "group and artist", filter: models.SongFilter{GroupID: &groupID, Artist: "Queen"}, want: []string{groupTag(groupID)}},
+		{name: "artist", filter: models.SongFilter{Artist: "Queen", Genre: "rock"}, want: []string{artistTag("Queen")}},
+		{name: "group name", filter: models.SongFilter{Group: "Queen"}, want: []string{listTag}},
+		{name: "song name", filter: models.SongFilter{Name: "Bohemian"}, want: []string{listTag}},
+		{name: "genre, tags and language", filter: models.SongFilter{Genre: "rock", Tags: []string{"live"}, Language: "en"}, want: []string{listTag}},
+		{name: "dates", filter: models.SongFilter{ReleasedFrom: &from}, want: []string{listTag}},
+		{name: "deleted", filter: models.SongFilter{Deleted: true}, want: []string{listTag}},
+		{name: "none", want: []string{listTag}},
+	}
+	for _, tt := range tests {
+		if got := filterTags(tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: filterTags = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSongTags(t *testing.T) {
+	oldGroup, newGroup := uuid.New(), uuid.New()
+	song := models.Song{Artists: []string{"Freddie Mercury", "Brian May"}, GroupID: &oldGroup}
+
+	want := []string{artistTag("Brian May"), artistTag("Freddie Mercury"), groupTag(oldGroup), listTag}
+	slices.Sort(want)
+	if got := songTags(&song); !reflect.DeepEqual(got, want) {
+		t.Errorf("songTags = %q, want %q", got, want)
+	}
+	if got := songTags(&models.Song{}); !reflect.DeepEqual(got, []string{listTag}) {
+		t.Errorf("songTags of a song without artists or group = %q, want %q", got, []string{listTag})
+	}
+
+	// An update passes the song as it was and as it is, so listings of the
+	// artists and group it left and of those it joined are all dropped.
+	updated := song
+	updated.Artists = []string{"brian  may", "Roger Taylor"}
+	updated.GroupID = &newGroup
+	want = []string{artistTag("Brian May"), artistTag("Freddie Mercury"), artistTag("Roger Taylor"), groupTag(oldGroup), groupTag(newGroup), listTag}
+	slices.Sort(want)
+	if got := songTags(&song, &updated); !reflect.DeepEqual(got, want) {
+		t.Errorf("songTags of an update = %q, want %q", got, want)
+	}
+
+	// The listings narrowed to the old and new artist and group are among them.
+	tags := songTags(&song, &updated)
+	for _, filter := range []models.SongFilter{
+		{GroupID: &oldGroup},
+		{GroupID: &newGroup},
+		{Artist: "FREDDIE MERCURY"},
+		{Artist: "Roger Taylor"},
+		{Name: "anything"},
+	} {
+		if tag := filterTags(filter)[0]; !slices.Contains(tags, tag) {
+			t.Errorf("update does not drop listings tagged %q for %+v", tag, filter)
+		}
+	}
+	if tag := filterTags(models.SongFilter{Artist: "John Deacon"})[0]; slices.Contains(tags, tag) {
+		t.Errorf("update drops listings of an unrelated artist, tagged %q", tag)
+	}
+}
+
+func TestNormalizeFilter(t *testing.T) {
+	tashkent := time.FixedZone("UZT", 5*60*60)
+	from := time.Date(2020, 1, 1, 5, 0, 0, 0, tashkent)
+	fromUTC := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := normalizeFilter(models.SongFilter{Artist: " Brian  MAY ", Genre: "Hard Rock", Tags: []string{"Live", "live", "Acoustic"}, ReleasedFrom: &from})
+	b := normalizeFilter(models.SongFilter{Artist: "brian may", Genre: "hard-rock", Tags: []string{"acoustic", "LIVE"}, ReleasedFrom: &fromUTC})
+	keyA, _ := json.Marshal(cachedPageQuery{Listing: "filter", Filter: &a})
+	keyB, _ := json.Marshal(cachedPageQuery{Listing: "filter", Filter: &b})
+	if string(keyA) != string(keyB) {
+		t.Errorf("equivalent filters have different cache keys:\n%s\n%s", keyA, keyB)
+	}
+	if want := []string{"acoustic", "live"}; !reflect.DeepEqual(a.Tags, want) {
+		t.Errorf("tags = %q, want %q", a.Tags, want)
+	}
+	if filterTags(a)[0] != artistTag(" Brian  MAY ") {
+		t.Errorf("normalized filter tagged %q, want %q", filterTags(a)[0], artistTag(" Brian  MAY "))
+	}
+	if from.Location() != tashkent {
+		t.Error("normalizeFilter changed the caller's time")
+	}
+}
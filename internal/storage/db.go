@@ -16,12 +16,32 @@ func GetDBConnection(cfg *config.Config) (*gorm.DB, error) {
 		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %s", err.Error())
 	}
 
-	err = db.AutoMigrate(&models.Song{})
+	err = db.AutoMigrate(
+		&models.Song{},
+		&models.Artist{},
+		&models.SongArtist{},
+		&models.Group{},
+		&models.GroupMember{},
+		&models.Album{},
+		&models.Release{},
+		&models.Track{},
+		&models.Genre{},
+		&models.SongGenre{},
+		&models.Tag{},
+		&models.SongTag{},
+		&models.Playlist{},
+		&models.PlaylistEntry{},
+		&models.User{},
+		&models.APIKey{},
+		&models.SongRevision{},
+		&models.SyncedLyrics{},
+		&models.SongTranslation{},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %s", err.Error())
 	}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// applySongFilter translates a SongFilter into WHERE clauses on the songs table.
+func applySongFilter(db *gorm.DB, filter models.SongFilter) *gorm.DB {
+	db = db.Where("is_deleted = ?", filter.Deleted)
+
+	if filter.Name != "" {
+		db = db.Where("name ILIKE ?", "%"+likeEscaper.Replace(filter.Name)+"%")
+	}
+	if filter.Group != "" {
+		db = db.Where(`"group" ILIKE ?`, "%"+likeEscaper.Replace(filter.Group)+"%")
+	}
+	if filter.GroupID != nil {
+		db = db.Where("group_id = ?", *filter.GroupID)
+	}
+	if filter.Artist != "" {
+		db = db.Where("id IN (?)", songIDsByArtistName(db, filter.Artist))
+	}
+	if filter.Genre != "" {
+		db = db.Where("id IN (?)", songIDsByGenreSlug(db, filter.Genre))
+	}
+	for _, tag := range filter.Tags {
+		db = db.Where("id IN (?)", songIDsByTag(db, tag))
+	}
+	if filter.Language != "" {
+		db = db.Where("language = ?", filter.Language)
+	}
+	if filter.ReleasedFrom != nil {
+		db = db.Where("release_date >= ?", *filter.ReleasedFrom)
+	}
+	if filter.ReleasedTo != nil {
+		db = db.Where("release_date <= ?", *filter.ReleasedTo)
+	}
+	if filter.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		db = db.Where("created_at <= ?", *filter.CreatedTo)
+	}
+	return db
+}
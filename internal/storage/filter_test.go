@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without a database, so tests can inspect the SQL.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("open dry run db: %v", err)
+	}
+	return db
+}
+
+const (
+	artistSubquery = `id IN (SELECT song_artists.song_id FROM "song_artists" JOIN artists ON artists.id = song_artists.artist_id WHERE artists.normalized_name = $%d)`
+	genreSubquery  = `id IN (SELECT song_id FROM "song_genres" WHERE genre_id IN ( WITH RECURSIVE subtree AS ( SELECT id FROM genres WHERE slug = $%d UNION ALL SELECT genres.id FROM genres JOIN subtree ON genres.parent_id = subtree.id ) SELECT id FROM subtree))`
+	tagSubquery    = `id IN (SELECT song_tags.song_id FROM "song_tags" JOIN tags ON tags.id = song_tags.tag_id WHERE tags.name = $%d)`
+)
+
+func TestApplySongFilter(t *testing.T) {
+	groupID := uuid.MustParse("7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23")
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 12, 31, 23, 59, 59, 999999000, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter models.SongFilter
+		where  []string
+		vars   []any
+	}{
+		{
+			name:  "no filter lists live songs",
+			where: []string{"is_deleted = $1"},
+			vars:  []any{false},
+		},
+		{
+			name:   "deleted",
+			filter: models.SongFilter{Deleted: true},
+			where:  []string{"is_deleted = $1"},
+			vars:   []any{true},
+		},
+		{
+			name:   "name is a case-insensitive substring with LIKE wildcards escaped",
+			filter: models.SongFilter{Name: `100%_a\b`},
+			where:  []string{"is_deleted = $1", "name ILIKE $2"},
+			vars:   []any{false, `%100\%\_a\\b%`},
+		},
+		{
+			name:   "group",
+			filter: models.SongFilter{Group: "Muse"},
+			where:  []string{"is_deleted = $1", `"group" ILIKE $2`},
+			vars:   []any{false, "%Muse%"},
+		},
+		{
+			name:   "group_id",
+			filter: models.SongFilter{GroupID: &groupID},
+			where:  []string{"is_deleted = $1", "group_id = $2"},
+			vars:   []any{false, groupID},
+		},
+		{
+			name:   "artist matches the normalized name",
+			filter: models.SongFilter{Artist: "  Freddie   MERCURY "},
+			where:  []string{"is_deleted = $1", fmt.Sprintf(artistSubquery, 2)},
+			vars:   []any{false, "freddie mercury"},
+		},
+		{
+			name:   "genre includes its subgenres",
+			filter: models.SongFilter{Genre: "Hip Hop"},
+			where:  []string{"is_deleted = $1", fmt.Sprintf(genreSubquery, 2)},
+			vars:   []any{false, "hip-hop"},
+		},
+		{
+			name:   "every tag is required",
+			filter: models.SongFilter{Tags: []string{"Live", "sad"}},
+			where:  []string{"is_deleted = $1", fmt.Sprintf(tagSubquery, 2), fmt.Sprintf(tagSubquery, 3)},
+			vars:   []any{false, "live", "sad"},
+		},
+		{
+			name:   "language",
+			filter: models.SongFilter{Language: "ru"},
+			where:  []string{"is_deleted = $1", "language = $2"},
+			vars:   []any{false, "ru"},
+		},
+		{
+			name:   "release date range",
+			filter: models.SongFilter{ReleasedFrom: &from, ReleasedTo: &to},
+			where:  []string{"is_deleted = $1", "release_date >= $2", "release_date <= $3"},
+			vars:   []any{false, from, to},
+		},
+		{
+			name:   "creation date range",
+			filter: models.SongFilter{CreatedFrom: &from, CreatedTo: &to},
+			where:  []string{"is_deleted = $1", "created_at >= $2", "created_at <= $3"},
+			vars:   []any{false, from, to},
+		},
+		{
+			name: "combined",
+			filter: models.SongFilter{
+				Name:         "love",
+				GroupID:      &groupID,
+				Artist:       "Queen",
+				Genre:        "rock",
+				Tags:         []string{"live"},
+				Language:     "en",
+				ReleasedFrom: &from,
+				CreatedTo:    &to,
+			},
+			where: []string{
+				"is_deleted = $1",
+				"name ILIKE $2",
+				"group_id = $3",
+				fmt.Sprintf(artistSubquery, 4),
+				fmt.Sprintf(genreSubquery, 5),
+				fmt.Sprintf(tagSubquery, 6),
+				"language = $7",
+				"release_date >= $8",
+				"created_at <= $9",
+			},
+			vars: []any{false, "%love%", groupID, "queen", "rock", "live", "en", from, to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var songs []models.Song
+			stmt := applySongFilter(dryRunDB(t), tt.filter).Find(&songs).Statement
+
+			want := `SELECT * FROM "songs" WHERE ` + strings.Join(tt.where, " AND ")
+			if got := strings.Join(strings.Fields(stmt.SQL.String()), " "); got != want {
+				t.Errorf("SQL\n got: %s\nwant: %s", got, want)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.vars) {
+				t.Errorf("vars = %#v, want %#v", stmt.Vars, tt.vars)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxTagFacets caps the number of tags counted in a listing's facets.
+const maxTagFacets = 20
+
+// genreSubtreeQuery selects the genre with the given slug and all of its descendants.
+const genreSubtreeQuery = `
+WITH RECURSIVE subtree AS (
+	SELECT id FROM genres WHERE slug = ?
+	UNION ALL
+	SELECT genres.id FROM genres JOIN subtree ON genres.parent_id = subtree.id
+)
+SELECT id FROM subtree`
+
+func (s *Storage) CreateGenre(ctx context.Context, genre *models.Genre) error {
+	if genre.Slug == "" {
+		genre.Slug = models.GenreSlug(genre.Name)
+	}
+	err := s.db.Omit(clause.Associations).Create(genre).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.ErrDuplicateGenre
+	}
+	return err
+}
+
+// GetGenres returns every genre arranged as a forest of root genres.
+func (s *Storage) GetGenres(ctx context.Context) ([]*models.Genre, error) {
+	var genres []*models.Genre
+	if err := s.db.Order("name, id").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Genre, len(genres))
+	for _, genre := range genres {
+		byID[genre.ID] = genre
+	}
+	roots := []*models.Genre{}
+	for _, genre := range genres {
+		if parent, ok := byID[ptrValue(genre.ParentID)]; ok {
+			parent.Children = append(parent.Children, genre)
+		} else {
+			roots = append(roots, genre)
+		}
+	}
+	return roots, nil
+}
+
+func (s *Storage) GetGenreByID(ctx context.Context, id string) (*models.Genre, error) {
+	genreUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var genre models.Genre
+	if err := s.db.Where("id = ?", genreUUID).First(&genre).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("parent_id = ?", genreUUID).Order("name, id").Find(&genre.Children).Error; err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// UpdateGenre renames or moves a genre, refusing moves under one of its own
+// descendants. Listings filtered by a genre include its subgenres, so the songs
+// of the whole subtree are touched, and cached listings are dropped even when
+// it has none, since they are keyed by slug.
+func (s *Storage) UpdateGenre(ctx context.Context, genre *models.Genre) error {
+	if genre.Slug == "" {
+		genre.Slug = models.GenreSlug(genre.Name)
+	}
+	var songIDs []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if genre.ParentID != nil {
+			var current models.Genre
+			if err := tx.Where("id = ?", genre.ID).First(&current).Error; err != nil {
+				return err
+			}
+			var cycles int64
+			if err := tx.Raw("SELECT count(*) FROM ("+genreSubtreeQuery+") AS subtree WHERE id = ?", current.Slug, *genre.ParentID).
+				Scan(&cycles).Error; err != nil {
+				return err
+			}
+			if cycles > 0 {
+				return models.ErrGenreCycle
+			}
+		}
+
+		result := tx.Model(&models.Genre{}).Where("id = ?", genre.ID).
+			Select("name", "slug", "parent_id").
+			Updates(genre)
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return models.ErrDuplicateGenre
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var err error
+		songIDs, err = touchSongs(tx, songIDsByGenreSlug(tx, genre.Slug))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return s.invalidateSongs(ctx, songIDs, listTag)
+}
+
+// DeleteGenre removes a genre without subgenres; songs lose that classification.
+// It has no subgenres, so its own songs are the only ones listed under it.
+func (s *Storage) DeleteGenre(ctx context.Context, id string) error {
+	genreUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	var songIDs []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&models.Genre{}).Where("parent_id = ?", genreUUID).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return models.ErrGenreHasChildren
+		}
+		var err error
+		if songIDs, err = touchSongs(tx, songIDsByGenre(tx, genreUUID)); err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", genreUUID).Delete(&models.Genre{})
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return models.ErrGenreHasChildren
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.invalidateSongs(ctx, songIDs, listTag)
+}
+
+func (s *Storage) GetSongGenres(ctx context.Context, songID string) ([]models.Genre, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	genres := []models.Genre{}
+	err = s.db.Joins("JOIN song_genres ON song_genres.genre_id = genres.id").
+		Where("song_genres.song_id = ?", songUUID).
+		Order("genres.name").
+		Find(&genres).Error
+	if err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// SetSongGenres replaces the genres a song is classified under.
+func (s *Storage) SetSongGenres(ctx context.Context, songID string, genreIDs []uuid.UUID) ([]models.Genre, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND is_deleted = false", songUUID).First(&models.Song{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("song_id = ?", songUUID).Delete(&models.SongGenre{}).Error; err != nil {
+			return err
+		}
+		if _, err := touchSongs(tx, []uuid.UUID{songUUID}); err != nil {
+			return err
+		}
+		if len(genreIDs) == 0 {
+			return nil
+		}
+		links := make([]models.SongGenre, 0, len(genreIDs))
+		for _, genreID := range genreIDs {
+			links = append(links, models.SongGenre{SongID: songUUID, GenreID: genreID})
+		}
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invalidateSongs(ctx, []string{songID}); err != nil {
+		return nil, err
+	}
+	return s.GetSongGenres(ctx, songID)
+}
+
+func (s *Storage) GetSongTags(ctx context.Context, songID string) ([]string, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	err = s.db.Model(&models.Tag{}).
+		Joins("JOIN song_tags ON song_tags.tag_id = tags.id").
+		Where("song_tags.song_id = ?", songUUID).
+		Order("tags.name").
+		Pluck("tags.name", &tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SetSongTags replaces the song's tags, creating tags that do not exist yet.
+func (s *Storage) SetSongTags(ctx context.Context, songID string, names []string) ([]string, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND is_deleted = false", songUUID).First(&models.Song{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("song_id = ?", songUUID).Delete(&models.SongTag{}).Error; err != nil {
+			return err
+		}
+		if _, err := touchSongs(tx, []uuid.UUID{songUUID}); err != nil {
+			return err
+		}
+
+		links := make([]models.SongTag, 0, len(names))
+		for _, name := range names {
+			name = models.NormalizeName(name)
+			if name == "" {
+				continue
+			}
+			err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+				Create(&models.Tag{ID: uuid.New(), Name: name}).Error
+			if err != nil {
+				return err
+			}
+			var tag models.Tag
+			if err := tx.Where("name = ?", name).First(&tag).Error; err != nil {
+				return err
+			}
+			links = append(links, models.SongTag{SongID: songUUID, TagID: tag.ID})
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invalidateSongs(ctx, []string{songID}); err != nil {
+		return nil, err
+	}
+	return s.GetSongTags(ctx, songID)
+}
+
+// tagFacets counts the tags of the songs selected by songs, most common first.
+func tagFacets(db *gorm.DB, songs *gorm.DB) ([]models.TagFacet, error) {
+	facets := []models.TagFacet{}
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Table("song_tags").
+		Select("tags.name AS tag, count(*) AS count").
+		Joins("JOIN tags ON tags.id = song_tags.tag_id").
+		Where("song_tags.song_id IN (?)", songs.Model(&models.Song{}).Select("id")).
+		Group("tags.name").
+		Order("count DESC, tags.name").
+		Limit(maxTagFacets).
+		Scan(&facets).Error
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// songIDsByGenreSlug selects the songs classified under the genre or any of its subgenres.
+func songIDsByGenreSlug(db *gorm.DB, slug string) *gorm.DB {
+	subtree := db.Session(&gorm.Session{NewDB: true}).Raw(genreSubtreeQuery, models.GenreSlug(slug))
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("song_genres").
+		Select("song_id").
+		Where("genre_id IN (?)", subtree)
+}
+
+// songIDsByGenre selects the songs classified directly under a genre.
+func songIDsByGenre(db *gorm.DB, genreID uuid.UUID) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("song_genres").
+		Select("song_id").
+		Where("genre_id = ?", genreID)
+}
+
+func songIDsByTag(db *gorm.DB, name string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("song_tags").
+		Select("song_tags.song_id").
+		Joins("JOIN tags ON tags.id = song_tags.tag_id").
+		Where("tags.name = ?", models.NormalizeName(name))
+}
+
+func ptrValue[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) CreateGroup(ctx context.Context, group *models.Group) error {
+	group.Name = models.CleanName(group.Name)
+	group.NormalizedName = models.NormalizeName(group.Name)
+	err := s.db.Create(group).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.ErrDuplicateGroup
+	}
+	return err
+}
+
+func (s *Storage) GetGroups(ctx context.Context, limit, offset int) ([]models.Group, error) {
+	groups := []models.Group{}
+	if err := s.db.Order("normalized_name, id").Limit(limit).Offset(offset).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (s *Storage) GetGroupByID(ctx context.Context, id string) (*models.Group, error) {
+	groupUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var group models.Group
+	if err := s.db.Where("id = ?", groupUUID).First(&group).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// UpdateGroup saves the group and renames it on every song that references it.
+func (s *Storage) UpdateGroup(ctx context.Context, group *models.Group) error {
+	group.Name = models.CleanName(group.Name)
+	group.NormalizedName = models.NormalizeName(group.Name)
+
+	var songIDs []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Group
+		if err := tx.Where("id = ?", group.ID).First(&current).Error; err != nil {
+			return err
+		}
+		group.CreatedAt = current.CreatedAt
+
+		if err := tx.Save(group).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return models.ErrDuplicateGroup
+			}
+			return err
+		}
+		if current.Name == group.Name {
+			return nil
+		}
+
+		if err := tx.Model(&models.Song{}).Where("group_id = ?", group.ID).Pluck("id", &songIDs).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Song{}).Where("group_id = ?", group.ID).
+			Updates(map[string]any{"group": group.Name, "version": gorm.Expr("version + 1")}).Error
+	})
+	if err != nil {
+		return err
+	}
+	return s.invalidateSongs(ctx, songIDs)
+}
+
+// DeleteGroup removes a group, and its membership history, when no song references it.
+func (s *Storage) DeleteGroup(ctx context.Context, id string) error {
+	groupUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var songs int64
+		if err := tx.Model(&models.Song{}).Where("group_id = ?", groupUUID).Count(&songs).Error; err != nil {
+			return err
+		}
+		if songs > 0 {
+			return models.ErrGroupInUse
+		}
+		result := tx.Where("id = ?", groupUUID).Delete(&models.Group{})
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return models.ErrGroupInUse
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// GetGroupMembers returns the group's whole membership history, earliest first.
+func (s *Storage) GetGroupMembers(ctx context.Context, groupID string) ([]models.GroupMember, error) {
+	groupUUID, err := uuid.Parse(groupID)
+	if err != nil {
+		return nil, err
+	}
+	members := []models.GroupMember{}
+	err = s.db.Preload("Artist").
+		Where("group_id = ?", groupUUID).
+		Order("joined_on NULLS FIRST, left_on NULLS LAST, id").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (s *Storage) AddGroupMember(ctx context.Context, member *models.GroupMember) error {
+	if err := s.db.Omit(clause.Associations).Create(member).Error; err != nil {
+		return err
+	}
+	return s.db.Preload("Artist").Where("id = ?", member.ID).First(member).Error
+}
+
+func (s *Storage) UpdateGroupMember(ctx context.Context, member *models.GroupMember) error {
+	result := s.db.Model(&models.GroupMember{}).
+		Where("id = ? AND group_id = ?", member.ID, member.GroupID).
+		Select("artist_id", "role", "joined_on", "left_on").
+		Updates(member)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return s.db.Preload("Artist").Where("id = ?", member.ID).First(member).Error
+}
+
+func (s *Storage) DeleteGroupMember(ctx context.Context, groupID, memberID string) error {
+	result := s.db.Where("id = ? AND group_id = ?", memberID, groupID).Delete(&models.GroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetGroupCatalogue returns the group's line-up on asOf and a page of the songs it had released by then.
+func (s *Storage) GetGroupCatalogue(ctx context.Context, groupID string, asOf time.Time, page models.PageRequest) (*models.GroupCatalogue, error) {
+	group, err := s.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineUp := []models.GroupMember{}
+	err = s.db.Preload("Artist").
+		Where("group_id = ?", group.ID).
+		Where("(joined_on IS NULL OR joined_on <= ?) AND (left_on IS NULL OR left_on > ?)", asOf, asOf).
+		Order("joined_on NULLS FIRST, id").
+		Find(&lineUp).Error
+	if err != nil {
+		return nil, err
+	}
+
+	songs, err := s.GetSongsWithFilters(ctx, models.SongFilter{GroupID: &group.ID, ReleasedTo: &asOf}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GroupCatalogue{
+		Group:    *group,
+		AsOf:     asOf,
+		LineUp:   lineUp,
+		SongPage: *songs,
+	}, nil
+}
+
+// resolveSongGroup points the song at a group. A given group_id wins and its
+// canonical name is copied to Group; otherwise the group is looked up by name
+// and created when it does not exist yet.
+func resolveSongGroup(tx *gorm.DB, song *models.Song) error {
+	if song.GroupID != nil {
+		var group models.Group
+		if err := tx.Where("id = ?", *song.GroupID).First(&group).Error; err != nil {
+			return err
+		}
+		song.Group = group.Name
+		return nil
+	}
+
+	if models.NormalizeName(song.Group) == "" {
+		return nil
+	}
+	candidate := models.Group{
+		ID:             uuid.New(),
+		Name:           models.CleanName(song.Group),
+		NormalizedName: models.NormalizeName(song.Group),
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "normalized_name"}},
+		DoNothing: true,
+	}).Create(&candidate).Error
+	if err != nil {
+		return err
+	}
+
+	var group models.Group
+	if err := tx.Where("normalized_name = ?", candidate.NormalizedName).First(&group).Error; err != nil {
+		return err
+	}
+	song.GroupID = &group.ID
+	song.Group = group.Name
+	return nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+// DetectSongLanguages detects the language of every song, including those in
+// the trash, and stores it where it changed, batchSize songs at a time. It is
+// for songs written before detection existed or after the model improved.
+// Changed songs move to a new version. It returns how many songs changed.
+func (s *Storage) DetectSongLanguages(ctx context.Context, batchSize int) (int, error) {
+	changed := 0
+	var after *uuid.UUID
+	for {
+		var songs []models.Song
+		query := s.db.WithContext(ctx).Select("id", "name", "lyrics", "language").Order("id").Limit(batchSize)
+		if after != nil {
+			query = query.Where("id > ?", *after)
+		}
+		if err := query.Find(&songs).Error; err != nil {
+			return changed, err
+		}
+		if len(songs) == 0 {
+			return changed, nil
+		}
+		after = &songs[len(songs)-1].ID
+
+		detected := detectLanguages(songs)
+		if len(detected) == 0 {
+			continue
+		}
+		var ids []string
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, song := range detected {
+				err := tx.Model(&models.Song{}).Where("id = ?", song.ID).Updates(map[string]any{
+					"language": song.Language,
+					"version":  gorm.Expr("version + 1"),
+				}).Error
+				if err != nil {
+					return err
+				}
+				ids = append(ids, song.ID.String())
+			}
+			return nil
+		})
+		if err != nil {
+			return changed, err
+		}
+		changed += len(ids)
+		if err := s.invalidateSongs(ctx, ids); err != nil {
+			return changed, err
+		}
+	}
+}
+
+// detectLanguages detects the language of the songs and returns those whose
+// language changed, with the new language set.
+func detectLanguages(songs []models.Song) []models.Song {
+	var changed []models.Song
+	for _, song := range songs {
+		detected := song
+		detected.DetectLanguage()
+		if detected.Language != song.Language {
+			changed = append(changed, detected)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+)
+
+func TestDetectLanguagesSkipsUnchangedSongs(t *testing.T) {
+	english := "Sing it loud, sing it bright,\nFeel the rhythm through the night."
+	russian := "Пой громко, пой ярко,\nЧувствуй ритм всю ночь."
+	songs := []models.Song{
+		{ID: uuid.New(), Lyrics: english, Language: "en"},
+		{ID: uuid.New(), Lyrics: russian, Language: ""},
+		{ID: uuid.New(), Lyrics: russian, Language: "en"},
+		{ID: uuid.New(), Name: "Hi", Lyrics: "la la", Language: ""},
+		{ID: uuid.New(), Name: "Night drive through the city", Language: "en"},
+		{ID: uuid.New(), Name: "Hi", Language: "ru"},
+	}
+
+	changed := detectLanguages(songs)
+	want := map[uuid.UUID]string{songs[1].ID: "ru", songs[2].ID: "ru", songs[5].ID: ""}
+	if len(changed) != len(want) {
+		t.Fatalf("changed %d songs, want %d: %+v", len(changed), len(want), changed)
+	}
+	for _, song := range changed {
+		language, ok := want[song.ID]
+		if !ok {
+			t.Errorf("song %s changed to %q, want it skipped", song.ID, song.Language)
+		} else if song.Language != language {
+			t.Errorf("song %s changed to %q, want %q", song.ID, song.Language, language)
+		}
+	}
+	if songs[2].Language != "en" {
+		t.Errorf("detecting changed the given song to %q", songs[2].Language)
+	}
+	if changed := detectLanguages(changed); len(changed) != 0 {
+		t.Errorf("detecting again changed %+v, want nothing", changed)
+	}
+}
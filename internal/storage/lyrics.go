@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/lrc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetSyncedLyrics stores the song's time-synced lyrics and replaces its plain
+// lyrics with their text, recording the previous state as a revision.
+func (s *Storage) SetSyncedLyrics(ctx context.Context, id string, lyrics *lrc.Lyrics, author models.Author, condition models.VersionCondition) (*models.Song, error) {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var song models.Song
+	var tags []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_deleted = false", songUUID).First(&song).Error
+		if err != nil {
+			return err
+		}
+		song.Lyrics = lyrics.Plain()
+		if tags, err = updateSong(tx, &song, author, condition); err != nil {
+			return err
+		}
+		synced := models.SyncedLyrics{SongID: songUUID, LRC: lyrics.Format(false), Enhanced: lyrics.Enhanced()}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&synced).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cacheSong(ctx, &song, tags); err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// GetSyncedLyrics returns the time-synced lyrics of a live song.
+func (s *Storage) GetSyncedLyrics(ctx context.Context, id string) (*models.SyncedLyrics, error) {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var synced models.SyncedLyrics
+	err = s.db.Joins("JOIN songs ON songs.id = synced_lyrics.song_id").
+		Where("synced_lyrics.song_id = ? AND songs.is_deleted = false", songUUID).
+		First(&synced).Error
+	if err != nil {
+		return nil, err
+	}
+	return &synced, nil
+}
+
+// DeleteSyncedLyrics removes the song's time-synced lyrics; its plain lyrics stay.
+func (s *Storage) DeleteSyncedLyrics(ctx context.Context, id string) error {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	result := s.db.Where("song_id = ?", songUUID).Delete(&models.SyncedLyrics{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/cursor"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 1000
+	defaultSort      = "created_at"
+)
+
+// sortKey describes a column songs can be keyset-paginated by. Ties are broken by id.
+type sortKey struct {
+	column string
+	value  func(*models.Song) string
+	parse  func(string) (any, error)
+}
+
+var sortKeys = map[string]sortKey{
+	"created_at": {
+		column: "created_at",
+		value:  func(s *models.Song) string { return s.CreatedAt.Format(time.RFC3339Nano) },
+		parse:  parseTimeKey,
+	},
+	"release_date": {
+		column: "release_date",
+		value:  func(s *models.Song) string { return s.ReleaseDate.Format(time.RFC3339Nano) },
+		parse:  parseTimeKey,
+	},
+	"name": {
+		column: "name",
+		value:  func(s *models.Song) string { return s.Name },
+		parse:  func(v string) (any, error) { return v, nil },
+	},
+}
+
+func parseTimeKey(v string) (any, error) {
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+// pageCursor is the signed position carried by next_cursor and prev_cursor.
+type pageCursor struct {
+	Sort     string    `json:"s"`
+	Key      string    `json:"k"`
+	ID       uuid.UUID `json:"i"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// pageLimit applies the default and maximum page sizes to a requested limit.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	return min(limit, maxPageLimit)
+}
+
+// pageWindow is a decoded page request: the sort, the position the page
+// starts from, and the order its rows are read in.
+type pageWindow struct {
+	sort       string
+	key        sortKey
+	limit      int
+	current    *pageCursor
+	descending bool
+}
+
+// backward reports whether the page is read towards the start of the listing.
+func (w *pageWindow) backward() bool {
+	return w.current != nil && w.current.Backward
+}
+
+// reverse reports whether rows are read in descending (column, id) order.
+// Walking backwards reads the preceding rows in reverse order and flips them afterwards.
+func (w *pageWindow) reverse() bool {
+	return w.descending != w.backward()
+}
+
+// paginateSongs runs query as a keyset-paginated listing described by page.
+func (s *Storage) paginateSongs(query *gorm.DB, page models.PageRequest) (*models.SongPage, error) {
+	window, err := s.pageWindow(page)
+	if err != nil {
+		return nil, err
+	}
+
+	op, dir := ">", "ASC"
+	if window.reverse() {
+		op, dir = "<", "DESC"
+	}
+	if window.current != nil {
+		keyValue, err := window.key.parse(window.current.Key)
+		if err != nil {
+			return nil, cursor.ErrInvalid
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", window.key.column, op), keyValue, window.current.ID)
+	}
+
+	var songs []models.Song
+	err = query.
+		Order(fmt.Sprintf("%s %s, id %s", window.key.column, dir, dir)).
+		Limit(window.limit + 1).
+		Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	return s.songPage(window, songs)
+}
+
+// pageWindow decodes and checks the cursor and sort of a page request.
+func (s *Storage) pageWindow(page models.PageRequest) (*pageWindow, error) {
+	window := &pageWindow{limit: pageLimit(page.Limit)}
+	if page.Cursor != "" {
+		window.current = &pageCursor{}
+		if err := s.cursors.Decode(page.Cursor, window.current); err != nil {
+			return nil, err
+		}
+		if page.Sort != "" && page.Sort != window.current.Sort {
+			return nil, fmt.Errorf("%w: cursor was issued for sort %q", cursor.ErrInvalid, window.current.Sort)
+		}
+		page.Sort = window.current.Sort
+	}
+	if page.Sort == "" {
+		page.Sort = defaultSort
+	}
+
+	key, ok := sortKeys[strings.TrimPrefix(page.Sort, "-")]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported sort %q", cursor.ErrInvalid, page.Sort)
+	}
+	window.sort, window.key = page.Sort, key
+	window.descending = strings.HasPrefix(page.Sort, "-")
+	return window, nil
+}
+
+// songPage builds the page from the rows read for window, up to limit + 1 of
+// them in read order, and signs the cursors to its neighbouring pages.
+func (s *Storage) songPage(window *pageWindow, songs []models.Song) (*models.SongPage, error) {
+	backward := window.backward()
+	hasMore := len(songs) > window.limit
+	if hasMore {
+		songs = songs[:window.limit]
+	}
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	result := &models.SongPage{Songs: songs}
+	if result.Songs == nil {
+		result.Songs = []models.Song{}
+	}
+
+	// A page reached through a cursor always has rows on the side it came from.
+	hasNext := hasMore || backward
+	hasPrev := (hasMore && backward) || (window.current != nil && !backward)
+
+	if len(songs) == 0 {
+		return result, nil
+	}
+
+	var err error
+	if hasNext {
+		last := &songs[len(songs)-1]
+		if result.NextCursor, err = s.cursors.Encode(pageCursor{Sort: window.sort, Key: window.key.value(last), ID: last.ID}); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		first := &songs[0]
+		if result.PrevCursor, err = s.cursors.Encode(pageCursor{Sort: window.sort, Key: window.key.value(first), ID: first.ID, Backward: true}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"github.com/ruziba3vich/music_lib/pkg/cursor"
+)
+
+// memoryPage pages through songs in memory the way paginateSongs queries
+// the database for window.
+func memoryPage(t *testing.T, s *Storage, songs []models.Song, page models.PageRequest) *models.SongPage {
+	t.Helper()
+	window, err := s.pageWindow(page)
+	if err != nil {
+		t.Fatalf("pageWindow(%+v): %v", page, err)
+	}
+	compare := func(a, b *models.Song) int {
+		if c := strings.Compare(window.key.value(a), window.key.value(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	}
+	sorted := slices.Clone(songs)
+	slices.SortFunc(sorted, func(a, b models.Song) int { return compare(&a, &b) })
+	if window.reverse() {
+		slices.Reverse(sorted)
+	}
+
+	var rows []models.Song
+	for _, song := range sorted {
+		if window.current != nil {
+			at := models.Song{ID: window.current.ID, Name: window.current.Key}
+			if window.key.column != "name" {
+				t.Fatalf("memoryPage only seeks by name, not %s", window.key.column)
+			}
+			c := compare(&song, &at)
+			if window.reverse() && c >= 0 || !window.reverse() && c <= 0 {
+				continue
+			}
+		}
+		if len(rows) == window.limit+1 {
+			break
+		}
+		rows = append(rows, song)
+	}
+	result, err := s.songPage(window, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func names(page *models.SongPage) string {
+	var names []string
+	for _, song := range page.Songs {
+		names = append(names, song.Name)
+	}
+	return strings.Join(names, "")
+}
+
+func TestPaginationBothWays(t *testing.T) {
+	s := &Storage{cursors: cursor.NewCodec([]byte("secret"))}
+	var songs []models.Song
+	for _, name := range []string{"d", "a", "g", "b", "e", "c", "f"} {
+		songs = append(songs, models.Song{ID: uuid.New(), Name: name})
+	}
+
+	for _, sort := range []string{"name", "-name"} {
+		pages := []string{"abc", "def", "g"}
+		if sort == "-name" {
+			pages = []string{"gfe", "dcb", "a"}
+		}
+
+		// Forwards from the first page to the last.
+		page := memoryPage(t, s, songs, models.PageRequest{Sort: sort, Limit: 3})
+		var cursors []string
+		for i, want := range pages {
+			if got := names(page); got != want {
+				t.Fatalf("%s: page %d = %q, want %q", sort, i+1, got, want)
+			}
+			hasPrev, hasNext := page.PrevCursor != "", page.NextCursor != ""
+			if hasPrev != (i > 0) || hasNext != (i < len(pages)-1) {
+				t.Errorf("%s: page %d has_prev %t, has_next %t", sort, i+1, hasPrev, hasNext)
+			}
+			cursors = append(cursors, page.PrevCursor)
+			if page.NextCursor != "" {
+				page = memoryPage(t, s, songs, models.PageRequest{Limit: 3, Cursor: page.NextCursor})
+			}
+		}
+
+		// Backwards from the last page to the first.
+		for i := len(pages) - 2; i >= 0; i-- {
+			page = memoryPage(t, s, songs, models.PageRequest{Limit: 3, Cursor: cursors[i+1]})
+			if got := names(page); got != pages[i] {
+				t.Fatalf("%s: back to page %d = %q, want %q", sort, i+1, got, pages[i])
+			}
+			hasPrev, hasNext := page.PrevCursor != "", page.NextCursor != ""
+			if hasPrev != (i > 0) || !hasNext {
+				t.Errorf("%s: back on page %d has_prev %t, has_next %t", sort, i+1, hasPrev, hasNext)
+			}
+			if i > 0 && page.PrevCursor != cursors[i] {
+				// The cursor is for the same song either way, so it is identical.
+				t.Errorf("%s: back on page %d prev_cursor differs from the one seen going forwards", sort, i+1)
+			}
+		}
+	}
+}
+
+func TestPaginationEdges(t *testing.T) {
+	s := &Storage{cursors: cursor.NewCodec([]byte("secret"))}
+	songs := []models.Song{{ID: uuid.New(), Name: "a"}, {ID: uuid.New(), Name: "b"}}
+
+	// A single page has neither neighbour.
+	page := memoryPage(t, s, songs, models.PageRequest{Sort: "name", Limit: 2})
+	if names(page) != "ab" || page.PrevCursor != "" || page.NextCursor != "" {
+		t.Errorf("only page = %q, prev %q, next %q", names(page), page.PrevCursor, page.NextCursor)
+	}
+
+	// Stepping to the next page and back returns to the first page, which
+	// has nothing before it.
+	page = memoryPage(t, s, songs, models.PageRequest{Sort: "name", Limit: 1})
+	page = memoryPage(t, s, songs, models.PageRequest{Limit: 1, Cursor: page.NextCursor})
+	page = memoryPage(t, s, songs, models.PageRequest{Limit: 1, Cursor: page.PrevCursor})
+	if names(page) != "a" || page.PrevCursor != "" || page.NextCursor == "" {
+		t.Errorf("back on the first page = %q, prev %q, next %q", names(page), page.PrevCursor, page.NextCursor)
+	}
+
+	page = memoryPage(t, s, nil, models.PageRequest{})
+	if page.Songs == nil || len(page.Songs) != 0 || page.PrevCursor != "" || page.NextCursor != "" {
+		t.Errorf("empty listing = %+v", page)
+	}
+}
+
+func TestPageWindowRejects(t *testing.T) {
+	s := &Storage{cursors: cursor.NewCodec([]byte("secret"))}
+	token, err := s.cursors.Encode(pageCursor{Sort: "name", Key: "a", ID: uuid.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := cursor.NewCodec([]byte("other secret")).Encode(pageCursor{Sort: "name", Key: "a", ID: uuid.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]models.PageRequest{
+		"unknown sort":          {Sort: "lyrics"},
+		"cursor for other sort": {Sort: "-name", Cursor: token},
+		"foreign secret":        {Cursor: foreign},
+		"tampered cursor":       {Cursor: "x" + token},
+		"malformed cursor":      {Cursor: "not a cursor"},
+	}
+	for name, page := range tests {
+		if _, err := s.pageWindow(page); !errors.Is(err, cursor.ErrInvalid) {
+			t.Errorf("%s: pageWindow error = %v, want cursor.ErrInvalid", name, err)
+		}
+	}
+
+	// The sort can be left out when a cursor is given; the cursor's applies.
+	window, err := s.pageWindow(models.PageRequest{Cursor: token})
+	if err != nil || window.sort != "name" {
+		t.Errorf("pageWindow with only a cursor = %+v, %v, want sort name", window, err)
+	}
+	if window, err := s.pageWindow(models.PageRequest{Limit: 5000}); err != nil || window.limit != maxPageLimit || window.sort != defaultSort {
+		t.Errorf("pageWindow defaults = %+v, %v", window, err)
+	}
+}
+
+func TestPaginateSongsQuery(t *testing.T) {
+	s := &Storage{cursors: cursor.NewCodec([]byte("secret"))}
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	id := uuid.MustParse("7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23")
+	forward, err := s.cursors.Encode(pageCursor{Sort: "-created_at", Key: created.Format(time.RFC3339Nano), ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backward, err := s.cursors.Encode(pageCursor{Sort: "-created_at", Key: created.Format(time.RFC3339Nano), ID: id, Backward: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for token, want := range map[string]string{
+		forward:  `(created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT $3`,
+		backward: `(created_at, id) > ($1, $2) ORDER BY created_at ASC, id ASC LIMIT $3`,
+	} {
+		db := dryRunDB(t)
+		s.db = db
+		query := db.Model(&models.Song{})
+		if _, err := s.paginateSongs(query, models.PageRequest{Limit: 3, Cursor: token}); err != nil {
+			t.Fatal(err)
+		}
+		sql := query.Statement.SQL.String()
+		if !strings.HasSuffix(sql, want) {
+			t.Errorf("query = %s\nwant it to end with %s", sql, want)
+		}
+		if vars := fmt.Sprint(query.Statement.Vars); !strings.Contains(vars, "2024-05-01 12:00:00") || !strings.HasSuffix(vars, " 4]") {
+			t.Errorf("vars = %s, want the cursor's time and a limit of 4", vars)
+		}
+	}
+}
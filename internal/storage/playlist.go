@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// positionStep is the gap between consecutive entries appended to a playlist.
+const positionStep = 1024
+
+// errPositionExhausted means two neighbouring entries are too close for a key to fit between them.
+var errPositionExhausted = errors.New("no room between playlist positions")
+
+// liveEntry hides entries whose song is soft-deleted. The entries are kept and
+// show up again once the song is restored.
+const liveEntry = "EXISTS (SELECT 1 FROM songs WHERE songs.id = playlist_entries.song_id AND songs.is_deleted = false)"
+
+func (s *Storage) CreatePlaylist(ctx context.Context, playlist *models.Playlist) error {
+	return s.db.Omit(clause.Associations).Create(playlist).Error
+}
+
+// GetPlaylists lists public playlists and those owned by viewerID, newest first.
+func (s *Storage) GetPlaylists(ctx context.Context, viewerID *uuid.UUID, limit, offset int) ([]models.Playlist, error) {
+	query := s.db.Where("is_public = true")
+	if viewerID != nil {
+		query = s.db.Where("is_public = true OR owner_id = ?", *viewerID)
+	}
+	playlists := []models.Playlist{}
+	err := query.Order("created_at DESC, id").Limit(limit).Offset(offset).Find(&playlists).Error
+	if err != nil {
+		return nil, err
+	}
+	return playlists, nil
+}
+
+// GetPlaylistByID returns the playlist with its visible entries in order.
+func (s *Storage) GetPlaylistByID(ctx context.Context, id string) (*models.Playlist, error) {
+	playlistUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var playlist models.Playlist
+	err = s.db.Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Where(liveEntry).Order("position, id")
+	}).Preload("Entries.Song").Where("id = ?", playlistUUID).First(&playlist).Error
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (s *Storage) UpdatePlaylist(ctx context.Context, playlist *models.Playlist) error {
+	return s.updateByID(&models.Playlist{}, playlist.ID, playlist, "name", "description", "is_public")
+}
+
+// DeletePlaylist removes the playlist and all of its entries.
+func (s *Storage) DeletePlaylist(ctx context.Context, id string) error {
+	return s.deleteByID(&models.Playlist{}, id)
+}
+
+// AddPlaylistEntry inserts a song at the given position. The same song may appear more than once.
+func (s *Storage) AddPlaylistEntry(ctx context.Context, playlistID string, input models.PlaylistEntryInput) (*models.PlaylistEntry, error) {
+	entry := models.PlaylistEntry{ID: uuid.New(), SongID: input.SongID}
+	err := s.editPlaylist(playlistID, func(tx *gorm.DB, playlistUUID uuid.UUID) error {
+		if err := tx.Where("id = ? AND is_deleted = false", input.SongID).First(&models.Song{}).Error; err != nil {
+			return err
+		}
+		position, err := placeEntry(tx, playlistUUID, entry.ID, input.EntryPosition)
+		if err != nil {
+			return err
+		}
+		entry.PlaylistID = playlistUUID
+		entry.Position = position
+		if err := tx.Omit(clause.Associations).Create(&entry).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Song").Where("id = ?", entry.ID).First(&entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// MovePlaylistEntry moves an entry next to another one. Only the moved entry's key changes.
+func (s *Storage) MovePlaylistEntry(ctx context.Context, playlistID, entryID string, position models.EntryPosition) (*models.PlaylistEntry, error) {
+	entryUUID, err := uuid.Parse(entryID)
+	if err != nil {
+		return nil, err
+	}
+	var entry models.PlaylistEntry
+	err = s.editPlaylist(playlistID, func(tx *gorm.DB, playlistUUID uuid.UUID) error {
+		if err := tx.Where("id = ? AND playlist_id = ?", entryUUID, playlistUUID).First(&entry).Error; err != nil {
+			return err
+		}
+		key, err := placeEntry(tx, playlistUUID, entryUUID, position)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&entry).Update("position", key).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Song").Where("id = ?", entryUUID).First(&entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *Storage) RemovePlaylistEntry(ctx context.Context, playlistID, entryID string) error {
+	result := s.db.Where("id = ? AND playlist_id = ?", entryID, playlistID).Delete(&models.PlaylistEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// editPlaylist runs edit in a transaction holding a lock on the playlist, so
+// concurrent edits cannot compute the same position key. When neighbouring keys
+// have run out of precision, the playlist is renumbered once and edit retried.
+func (s *Storage) editPlaylist(playlistID string, edit func(tx *gorm.DB, playlistUUID uuid.UUID) error) error {
+	playlistUUID, err := uuid.Parse(playlistID)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var playlist models.Playlist
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", playlistUUID).First(&playlist).Error; err != nil {
+			return err
+		}
+		return retryRenumbered(
+			func() error { return edit(tx, playlistUUID) },
+			func() error { return renumberEntries(tx, playlistUUID) },
+		)
+	})
+}
+
+// retryRenumbered runs edit and, if it ran out of room between keys, runs
+// renumber and then edit once more.
+func retryRenumbered(edit, renumber func() error) error {
+	err := edit()
+	if !errors.Is(err, errPositionExhausted) {
+		return err
+	}
+	if err := renumber(); err != nil {
+		return err
+	}
+	return edit()
+}
+
+// placeEntry computes the position key for entryID, ignoring the entry's current key.
+func placeEntry(tx *gorm.DB, playlistID, entryID uuid.UUID, position models.EntryPosition) (float64, error) {
+	others := tx.Model(&models.PlaylistEntry{}).Where("playlist_id = ? AND id <> ?", playlistID, entryID)
+
+	var anchor models.PlaylistEntry
+	switch {
+	case position.After != nil:
+		if err := others.Session(&gorm.Session{}).Where("id = ?", *position.After).First(&anchor).Error; err != nil {
+			return 0, err
+		}
+		var next []models.PlaylistEntry
+		err := others.Session(&gorm.Session{}).Where("position > ?", anchor.Position).Order("position").Limit(1).Find(&next).Error
+		if err != nil {
+			return 0, err
+		}
+		if len(next) == 0 {
+			return entryKey(&anchor.Position, nil)
+		}
+		return entryKey(&anchor.Position, &next[0].Position)
+
+	case position.Before != nil:
+		if err := others.Session(&gorm.Session{}).Where("id = ?", *position.Before).First(&anchor).Error; err != nil {
+			return 0, err
+		}
+		var prev []models.PlaylistEntry
+		err := others.Session(&gorm.Session{}).Where("position < ?", anchor.Position).Order("position DESC").Limit(1).Find(&prev).Error
+		if err != nil {
+			return 0, err
+		}
+		if len(prev) == 0 {
+			return entryKey(nil, &anchor.Position)
+		}
+		return entryKey(&prev[0].Position, &anchor.Position)
+
+	default:
+		var last float64
+		if err := others.Session(&gorm.Session{}).Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return 0, err
+		}
+		return entryKey(&last, nil)
+	}
+}
+
+// entryKey returns the key for an entry placed between the keys prev and
+// next. A nil key means there is no entry on that side; prev and next are
+// never both nil.
+func entryKey(prev, next *float64) (float64, error) {
+	switch {
+	case next == nil:
+		return *prev + positionStep, nil
+	case prev == nil:
+		return *next - positionStep, nil
+	default:
+		return between(*prev, *next)
+	}
+}
+
+// between returns a key strictly between lo and hi.
+func between(lo, hi float64) (float64, error) {
+	mid := lo + (hi-lo)/2
+	if mid <= lo || mid >= hi {
+		return 0, errPositionExhausted
+	}
+	return mid, nil
+}
+
+// renumberEntries spreads the playlist's keys evenly again, keeping their order.
+func renumberEntries(tx *gorm.DB, playlistID uuid.UUID) error {
+	var entries []models.PlaylistEntry
+	if err := tx.Where("playlist_id = ?", playlistID).Order("position, id").Find(&entries).Error; err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if err := tx.Model(&entry).Update("position", float64(i+1)*positionStep).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
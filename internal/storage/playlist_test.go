@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEntryKey(t *testing.T) {
+	key := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name       string
+		prev, next *float64
+		want       float64
+	}{
+		{name: "after the last entry", prev: key(2048), want: 2048 + positionStep},
+		{name: "at the end of an empty playlist", prev: key(0), want: positionStep},
+		{name: "before the first entry", next: key(1024), want: 1024 - positionStep},
+		{name: "before a first entry with a negative key", next: key(-1024), want: -1024 - positionStep},
+		{name: "between two entries", prev: key(1024), next: key(2048), want: 1536},
+		{name: "between close entries", prev: key(1), next: key(1.5), want: 1.25},
+	}
+	for _, tt := range tests {
+		got, err := entryKey(tt.prev, tt.next)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: entryKey = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestBetweenRunsOutOfPrecision(t *testing.T) {
+	// Inserting again and again right after the same entry halves the gap
+	// each time, until no key fits.
+	lo, hi := 1024.0, 2048.0
+	inserts := 0
+	for {
+		mid, err := between(lo, hi)
+		if errors.Is(err, errPositionExhausted) {
+			break
+		}
+		if err != nil || mid <= lo || mid >= hi {
+			t.Fatalf("between(%v, %v) = %v, %v", lo, hi, mid, err)
+		}
+		hi = mid
+		inserts++
+	}
+	if inserts < 40 || inserts > 60 {
+		t.Errorf("%d inserts fit between 1024 and 2048, want about 52", inserts)
+	}
+
+	for _, keys := range [][2]float64{{1, 1}, {1, math.Nextafter(1, 2)}, {2, 1}} {
+		if _, err := between(keys[0], keys[1]); !errors.Is(err, errPositionExhausted) {
+			t.Errorf("between(%v, %v) error = %v, want errPositionExhausted", keys[0], keys[1], err)
+		}
+	}
+}
+
+func TestRetryRenumbered(t *testing.T) {
+	// keys are a playlist's keys in order. Every edit inserts a new entry
+	// right after the first one, so the gap there keeps shrinking until the
+	// playlist has to be renumbered.
+	keys := []float64{positionStep, 2 * positionStep}
+	renumbers := 0
+	renumber := func() error {
+		for i := range keys {
+			keys[i] = float64(i+1) * positionStep
+		}
+		renumbers++
+		return nil
+	}
+	for i := 0; i < 200; i++ {
+		err := retryRenumbered(func() error {
+			key, err := entryKey(&keys[0], &keys[1])
+			if err != nil {
+				return err
+			}
+			keys = slices.Insert(keys, 1, key)
+			return nil
+		}, renumber)
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+	}
+	if len(keys) != 202 {
+		t.Errorf("playlist has %d entries, want 202", len(keys))
+	}
+	if renumbers == 0 {
+		t.Error("playlist was never renumbered")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i] <= keys[i-1] {
+			t.Fatalf("keys out of order at %d: %v <= %v", i, keys[i], keys[i-1])
+		}
+	}
+}
+
+func TestRetryRenumberedErrors(t *testing.T) {
+	failed := errors.New("failed")
+	tests := []struct {
+		name        string
+		edits       []error
+		renumberErr error
+		want        error
+		renumbers   int
+	}{
+		{name: "success", edits: []error{nil}, want: nil},
+		{name: "other errors are not retried", edits: []error{failed}, want: failed},
+		{name: "retried after renumbering", edits: []error{errPositionExhausted, nil}, want: nil, renumbers: 1},
+		{name: "retried only once", edits: []error{errPositionExhausted, errPositionExhausted}, want: errPositionExhausted, renumbers: 1},
+		{name: "renumbering fails", edits: []error{errPositionExhausted}, renumberErr: failed, want: failed, renumbers: 1},
+	}
+	for _, tt := range tests {
+		edits, renumbers := 0, 0
+		err := retryRenumbered(func() error {
+			edits++
+			if edits > len(tt.edits) {
+				t.Fatalf("%s: edit run %d times", tt.name, edits)
+			}
+			return tt.edits[edits-1]
+		}, func() error {
+			renumbers++
+			return tt.renumberErr
+		})
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+		if edits != len(tt.edits) || renumbers != tt.renumbers {
+			t.Errorf("%s: %d edits and %d renumbers, want %d and %d", tt.name, edits, renumbers, len(tt.edits), tt.renumbers)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+// GetSongRevisions lists a song's revisions, newest first.
+func (s *Storage) GetSongRevisions(ctx context.Context, songID string, limit, offset int) ([]models.SongRevision, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := []models.SongRevision{}
+	err = s.db.Where("song_id = ?", songUUID).Order("number DESC").Limit(limit).Offset(offset).Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (s *Storage) GetSongRevision(ctx context.Context, songID string, number int) (*models.SongRevision, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	var revision models.SongRevision
+	if err := s.db.Where("song_id = ? AND number = ?", songUUID, number).First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// RollbackSong restores the state saved in a revision. The rollback is itself
+// an update, so the state it replaces is kept as a new revision.
+func (s *Storage) RollbackSong(ctx context.Context, songID string, number int, author models.Author) (*models.Song, error) {
+	songUUID, err := uuid.Parse(songID)
+	if err != nil {
+		return nil, err
+	}
+	var song models.Song
+	var tags []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var revision models.SongRevision
+		if err := tx.Where("song_id = ? AND number = ?", songUUID, number).First(&revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND is_deleted = false", songUUID).First(&song).Error; err != nil {
+			return err
+		}
+		song.ApplyState(revision.State)
+		var err error
+		tags, err = updateSong(tx, &song, author, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cacheSong(ctx, &song, tags); err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// createRevision records song's state as the song's next revision. The caller
+// must hold a lock on the song row so that numbers are not handed out twice.
+func createRevision(tx *gorm.DB, song *models.Song, author models.Author) error {
+	var last int
+	err := tx.Model(&models.SongRevision{}).Where("song_id = ?", song.ID).Select("COALESCE(MAX(number), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.SongRevision{
+		ID:     uuid.New(),
+		SongID: song.ID,
+		Number: last + 1,
+		Author: author,
+		State:  song.State(),
+	}).Error
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+// searchQuery ranks songs by the weighted search_vector column (name > group > lyrics)
+// and highlights the verse that matches the query best. Each song is indexed
+// with the text search configuration of its language, so the query is parsed
+// with that configuration too; the first condition ORs the query as each
+// configuration reads it, which matches a superset and keeps the index usable.
+const searchQuery = `
+SELECT songs.*,
+	ts_rank_cd(songs.search_vector, query) AS rank,
+	coalesce((
+		SELECT ts_headline(config, verse, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')
+		FROM unnest(string_to_array(replace(songs.lyrics, E'\r\n', E'\n'), E'\n\n')) AS verse
+		WHERE to_tsvector(config, verse) @@ query
+		ORDER BY ts_rank(to_tsvector(config, verse), query) DESC
+		LIMIT 1
+	), '') AS snippet
+FROM songs,
+	song_ts_config(songs.language) AS config,
+	websearch_to_tsquery(config, @query) AS query
+WHERE songs.search_vector @@ (
+		websearch_to_tsquery('english', @query) ||
+		websearch_to_tsquery('russian', @query) ||
+		websearch_to_tsquery('simple', @query)
+	)
+	AND songs.search_vector @@ query
+	AND songs.is_deleted = false
+	AND (@language = '' OR songs.language = @language)
+ORDER BY rank DESC, songs.id
+LIMIT @limit OFFSET @offset`
+
+// fuzzySearchQuery scores songs by the best trigram similarity of the query to the name,
+// the group or any single artist. The % operator keeps the trigram indexes usable and
+// honours pg_trgm.similarity_threshold, which is set per transaction.
+const fuzzySearchQuery = `
+SELECT songs.*,
+	greatest(
+		similarity(songs.name, @query),
+		similarity(songs."group", @query),
+		coalesce((SELECT max(similarity(artist, @query)) FROM unnest(songs.artists) AS artist), 0)
+	) AS rank
+FROM songs
+WHERE songs.is_deleted = false
+	AND (@language = '' OR songs.language = @language)
+	AND (
+		songs.name % @query
+		OR songs."group" % @query
+		OR EXISTS (SELECT 1 FROM unnest(songs.artists) AS artist WHERE artist % @query)
+	)
+ORDER BY rank DESC, songs.id
+LIMIT @limit OFFSET @offset`
+
+// SearchSongs runs a full-text search, restricted to songs in language unless it is empty.
+func (s *Storage) SearchSongs(ctx context.Context, query, language string, limit, offset int) ([]models.SearchResult, error) {
+	results := []models.SearchResult{}
+	err := s.db.Raw(searchQuery, map[string]any{
+		"query":    query,
+		"language": language,
+		"limit":    limit,
+		"offset":   offset,
+	}).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FuzzySearchSongs runs a trigram search, restricted to songs in language unless it is empty.
+func (s *Storage) FuzzySearchSongs(ctx context.Context, query, language string, threshold float64, limit, offset int) ([]models.SearchResult, error) {
+	results := []models.SearchResult{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		value := strconv.FormatFloat(threshold, 'f', -1, 64)
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", value).Error; err != nil {
+			return err
+		}
+		return tx.Raw(fuzzySearchQuery, map[string]any{
+			"query":    query,
+			"language": language,
+			"limit":    limit,
+			"offset":   offset,
+		}).Scan(&results).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
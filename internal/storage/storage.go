@@ -2,49 +2,77 @@ package storage
 
 import (
 	"context"
-	"strings"
 
 	"github.com/google/uuid"
 	"github.com/ruziba3vich/music_lib/internal/models"
 	redisservice "github.com/ruziba3vich/music_lib/internal/redis_service"
+	"github.com/ruziba3vich/music_lib/pkg/cursor"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Storage struct {
 	db           *gorm.DB
 	redisservice *redisservice.RedisService
+	cursors      *cursor.Codec
 }
 
-func NewStorage(db *gorm.DB, redisservice *redisservice.RedisService) *Storage {
+func NewStorage(db *gorm.DB, redisservice *redisservice.RedisService, cursors *cursor.Codec) *Storage {
 	return &Storage{
 		db:           db,
 		redisservice: redisservice,
+		cursors:      cursors,
 	}
 }
 
 func (s *Storage) CreateSong(ctx context.Context, song *models.Song) error {
-	if err := s.redisservice.AddSong(ctx, song); err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolveSongGroup(tx, song); err != nil {
+			return err
+		}
+		song.DetectLanguage()
+		if err := tx.Create(song).Error; err != nil {
+			return err
+		}
+		return syncSongArtists(tx, song)
+	})
+	if err != nil {
 		return err
 	}
-	return s.db.Create(song).Error
+	return s.cacheSong(ctx, song, songTags(song))
 }
 
-func (s *Storage) GetSongsWithFilters(ctx context.Context, filter map[string]any, limit, offset int) ([]models.Song, error) {
-	var songs []models.Song
-	query := s.db.Where(filter).Where("is_deleted = false").Limit(limit).Offset(offset)
-	if err := query.Find(&songs).Error; err != nil {
-		return nil, err
-	}
-	return songs, nil
+// GetSongsWithFilters returns a page of matching songs along with tag counts
+// over all matches. Pages are cached under the normalized filter.
+func (s *Storage) GetSongsWithFilters(ctx context.Context, filter models.SongFilter, page models.PageRequest) (*models.SongPage, error) {
+	filter = normalizeFilter(filter)
+	query := newCachedPageQuery("filtered", page)
+	query.Filter = &filter
+	return s.cachedSongPage(ctx, query, filterTags(filter), func() (*models.SongPage, error) {
+		return s.songPageWithFacets(func() *gorm.DB { return applySongFilter(s.db, filter) }, page)
+	})
 }
 
-func (s *Storage) GetSongs(ctx context.Context, limit, offset int) ([]models.Song, error) {
-	var songs []models.Song
-	query := s.db.Where("is_deleted = false").Limit(limit).Offset(offset)
-	if err := query.Find(&songs).Error; err != nil {
+// GetSongs returns a page of all live songs along with tag counts over all of
+// them. Pages are cached.
+func (s *Storage) GetSongs(ctx context.Context, page models.PageRequest) (*models.SongPage, error) {
+	return s.cachedSongPage(ctx, newCachedPageQuery("all", page), []string{listTag}, func() (*models.SongPage, error) {
+		return s.songPageWithFacets(func() *gorm.DB { return s.db.Where("is_deleted = false") }, page)
+	})
+}
+
+// songPageWithFacets loads a page of the songs selected by songs and counts the
+// tags across all of them. songs builds a fresh query for each use, since
+// paginating modifies the query it is given.
+func (s *Storage) songPageWithFacets(songs func() *gorm.DB, page models.PageRequest) (*models.SongPage, error) {
+	result, err := s.paginateSongs(songs(), page)
+	if err != nil {
 		return nil, err
 	}
-	return songs, nil
+	if result.TagFacets, err = tagFacets(s.db, songs()); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (s *Storage) GetSongByID(ctx context.Context, id string) (*models.Song, error) {
@@ -63,48 +91,209 @@ func (s *Storage) GetSongByID(ctx context.Context, id string) (*models.Song, err
 	return &song, nil
 }
 
-func (s *Storage) UpdateSong(ctx context.Context, song *models.Song) error {
-	if err := s.redisservice.AddSong(ctx, song); err != nil {
+// UpdateSong saves the song and records its previous state as a new revision
+// by author. It fails with ErrVersionMismatch unless condition allows the stored version.
+func (s *Storage) UpdateSong(ctx context.Context, song *models.Song, author models.Author, condition models.VersionCondition) error {
+	var tags []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		tags, err = updateSong(tx, song, author, condition)
+		return err
+	})
+	if err != nil {
 		return err
 	}
-	return s.db.Where("id = ? AND is_deleted = false", song.ID).Save(song).Error
+	return s.cacheSong(ctx, song, tags)
 }
 
-func (s *Storage) DeleteSong(ctx context.Context, id string) error {
+// PatchSong applies patch to the song's current state and saves the result,
+// holding a lock on the song so concurrent edits cannot be lost.
+func (s *Storage) PatchSong(ctx context.Context, id string, patch func(models.SongState) (models.SongState, error), author models.Author, condition models.VersionCondition) (*models.Song, error) {
 	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var song models.Song
+	var tags []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_deleted = false", songUUID).First(&song).Error
+		if err != nil {
+			return err
+		}
+		if !condition.Allows(song.Version) {
+			return models.ErrVersionMismatch
+		}
+		state, err := patch(song.State())
+		if err != nil {
+			return err
+		}
+		song.ApplyState(state)
+		tags, err = updateSong(tx, &song, author, condition)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cacheSong(ctx, &song, tags); err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// updateSong locks the live song, records its current state as a revision and
+// saves song over it as the next version. Changing the lyrics drops their
+// synced version. It returns the tags of the cached listings the song
+// appeared in before and after the change.
+func updateSong(tx *gorm.DB, song *models.Song, author models.Author, condition models.VersionCondition) ([]string, error) {
+	var current models.Song
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND is_deleted = false", song.ID).First(&current).Error
+	if err != nil {
+		return nil, err
+	}
+	if !condition.Allows(current.Version) {
+		return nil, models.ErrVersionMismatch
+	}
+	if err := createRevision(tx, &current, author); err != nil {
+		return nil, err
+	}
+	if err := resolveSongGroup(tx, song); err != nil {
+		return nil, err
+	}
+	song.CreatedAt = current.CreatedAt
+	song.Version = current.Version + 1
+	song.DetectLanguage()
+	if err := tx.Save(song).Error; err != nil {
+		return nil, err
+	}
+	if song.Lyrics != current.Lyrics {
+		// Synced lyrics no longer match the text they were derived from.
+		if err := tx.Where("song_id = ?", song.ID).Delete(&models.SyncedLyrics{}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := syncSongArtists(tx, song); err != nil {
+		return nil, err
+	}
+	return songTags(&current, song), nil
+}
+
+// DeleteSong moves a live song to the trash, from where it can be restored or
+// purged. It fails with ErrVersionMismatch unless condition allows the stored version.
+func (s *Storage) DeleteSong(ctx context.Context, id string, condition models.VersionCondition) error {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	var song models.Song
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_deleted = false", songUUID).First(&song).Error
+		if err != nil {
+			return err
+		}
+		if !condition.Allows(song.Version) {
+			return models.ErrVersionMismatch
+		}
+		return tx.Model(&song).Updates(map[string]any{"is_deleted": true, "deleted_at": gorm.Expr("NOW()")}).Error
+	})
 	if err != nil {
 		return err
 	}
 	if err := s.redisservice.DeleteSong(ctx, id); err != nil {
 		return err
 	}
-	return s.db.Model(&models.Song{}).Where("id = ?", songUUID).Update("is_deleted", true).Error
+	return s.redisservice.InvalidatePages(ctx, songTags(&song)...)
 }
 
+// GetSongLyricsPaginated pages through the song's lyrics section by section,
+// with repeated sections written out and labels left off.
 func (s *Storage) GetSongLyricsPaginated(ctx context.Context, id string, limit, offset int) ([]string, error) {
+	sections, err := s.GetSongLyricsSections(ctx, id, "", true)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(models.LyricsVerses(sections), limit, offset), nil
+}
+
+// paginate returns the page of items starting at offset, empty past the end.
+func paginate[T any](items []T, limit, offset int) []T {
+	start := max(offset, 0)
+	end := start + max(limit, 0)
+	if start >= len(items) {
+		return []T{}
+	}
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[start:end]
+}
+
+// GetSongLyricsSections parses the song's lyrics into sections, keeping only
+// those of sectionType unless it is empty. With expand, sections that repeat
+// an earlier one carry its lines.
+func (s *Storage) GetSongLyricsSections(ctx context.Context, id, sectionType string, expand bool) ([]models.LyricsSection, error) {
 	song, err := s.GetSongByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	verses := strings.Split(song.Lyrics, "\n\n")
+	sections := models.ParseLyrics(song.Lyrics)
+	if expand {
+		sections = models.ExpandLyrics(sections)
+	}
+	if sectionType == "" {
+		return sections, nil
+	}
+	matching := []models.LyricsSection{}
+	for _, section := range sections {
+		if section.Type == sectionType {
+			matching = append(matching, section)
+		}
+	}
+	return matching, nil
+}
 
-	start := offset
-	end := offset + limit
-	if start >= len(verses) {
-		return []string{}, nil
+// GetSongLyricsSection returns the section at the given index of the song's lyrics.
+func (s *Storage) GetSongLyricsSection(ctx context.Context, id string, index int, expand bool) (*models.LyricsSection, error) {
+	sections, err := s.GetSongLyricsSections(ctx, id, "", expand)
+	if err != nil {
+		return nil, err
 	}
-	if end > len(verses) {
-		end = len(verses)
+	if index < 0 || index >= len(sections) {
+		return nil, gorm.ErrRecordNotFound
 	}
+	return &sections[index], nil
+}
 
-	return verses[start:end], nil
+// GetSongsByArtist returns a page of the artist's live songs along with tag
+// counts over all of them. Pages are cached.
+func (s *Storage) GetSongsByArtist(ctx context.Context, artist string, page models.PageRequest) (*models.SongPage, error) {
+	query := newCachedPageQuery("artist", page)
+	query.Artist = models.NormalizeName(artist)
+	return s.cachedSongPage(ctx, query, []string{artistTag(artist)}, func() (*models.SongPage, error) {
+		return s.songPageWithFacets(func() *gorm.DB {
+			return s.db.Where("is_deleted = false AND id IN (?)", songIDsByArtistName(s.db, artist))
+		}, page)
+	})
 }
 
-func (s *Storage) GetSongsByArtist(ctx context.Context, artist string, limit int, offset int) ([]models.Song, error) {
+// touchSongs moves songs to a new version after a change to data shown with
+// them, such as their genres, tags or releases, so clients holding the old
+// version see that it changed. songIDs is a list of IDs or a subquery. The IDs
+// are returned so the caller can drop the cached copies.
+func touchSongs(tx *gorm.DB, songIDs any) ([]string, error) {
 	var songs []models.Song
-	query := s.db.Where("? = ANY(artists) AND is_deleted = false", artist).Limit(limit).Offset(offset).Find(&songs)
-	if query.Error != nil {
-		return nil, query.Error
+	err := tx.Model(&songs).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("id IN (?)", songIDs).
+		Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(songs))
+	for _, song := range songs {
+		ids = append(ids, song.ID.String())
 	}
-	return songs, nil
+	return ids, nil
 }
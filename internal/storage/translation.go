@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetSongTranslation stores a translation of the song's lyrics, which must
+// have exactly one line per line of the original.
+func (s *Storage) SetSongTranslation(ctx context.Context, id, language string, lines []string) (*models.SongTranslation, error) {
+	song, err := s.GetSongByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	original := models.LyricsLines(models.ParseLyrics(song.Lyrics))
+	if len(lines) != len(original) {
+		return nil, fmt.Errorf("%w: it has %d lines, the lyrics have %d", models.ErrMisaligned, len(lines), len(original))
+	}
+
+	translation := models.SongTranslation{
+		SongID:     song.ID,
+		Language:   language,
+		Lines:      lines,
+		SourceHash: models.LinesHash(original),
+	}
+	err = s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&translation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// GetSongTranslations lists the song's translations by language.
+func (s *Storage) GetSongTranslations(ctx context.Context, id string) ([]models.SongTranslation, error) {
+	song, err := s.GetSongByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	translations := []models.SongTranslation{}
+	if err := s.db.Where("song_id = ?", song.ID).Order("language").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	hash := models.LinesHash(models.LyricsLines(models.ParseLyrics(song.Lyrics)))
+	for i := range translations {
+		translations[i].Outdated = translations[i].SourceHash != hash
+	}
+	return translations, nil
+}
+
+// GetSongTranslation returns the song's translation into language.
+func (s *Storage) GetSongTranslation(ctx context.Context, id, language string) (*models.SongTranslation, error) {
+	song, translation, err := s.getSongTranslation(ctx, id, language)
+	if err != nil {
+		return nil, err
+	}
+	hash := models.LinesHash(models.LyricsLines(models.ParseLyrics(song.Lyrics)))
+	translation.Outdated = translation.SourceHash != hash
+	return translation, nil
+}
+
+// GetTranslatedLyricsPaginated pages through the song's lyrics like
+// GetSongLyricsPaginated, pairing each section with its translation. An
+// outdated translation is still paired line by line, with every verse marked
+// outdated.
+func (s *Storage) GetTranslatedLyricsPaginated(ctx context.Context, id, language string, limit, offset int) ([]models.TranslatedVerse, error) {
+	song, translation, err := s.getSongTranslation(ctx, id, language)
+	if err != nil {
+		return nil, err
+	}
+	sections := models.ParseLyrics(song.Lyrics)
+	verses := models.TranslateSections(sections, translation.Lines)
+	if translation.SourceHash != models.LinesHash(models.LyricsLines(sections)) {
+		for i := range verses {
+			verses[i].Outdated = true
+		}
+	}
+	return paginate(verses, limit, offset), nil
+}
+
+// DeleteSongTranslation removes the song's translation into language.
+func (s *Storage) DeleteSongTranslation(ctx context.Context, id, language string) error {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	result := s.db.Where("song_id = ? AND language = ?", songUUID, language).Delete(&models.SongTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *Storage) getSongTranslation(ctx context.Context, id, language string) (*models.Song, *models.SongTranslation, error) {
+	song, err := s.GetSongByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	var translation models.SongTranslation
+	if err := s.db.Where("song_id = ? AND language = ?", song.ID, language).First(&translation).Error; err != nil {
+		return nil, nil, err
+	}
+	return song, &translation, nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// purgeBatchSize bounds how many songs one statement of PurgeTrash deletes.
+const purgeBatchSize = 500
+
+// GetTrash lists deleted songs, most recently deleted first.
+func (s *Storage) GetTrash(ctx context.Context, limit, offset int) ([]models.Song, error) {
+	songs := []models.Song{}
+	err := s.db.Where("is_deleted = true").Order("deleted_at DESC, id").Limit(limit).Offset(offset).Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
+
+// RestoreSong takes a song out of the trash and caches it again.
+func (s *Storage) RestoreSong(ctx context.Context, id string) (*models.Song, error) {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var song models.Song
+	result := s.db.Model(&song).Clauses(clause.Returning{}).Where("id = ? AND is_deleted = true", songUUID).
+		Updates(map[string]any{"is_deleted": false, "deleted_at": nil})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := s.cacheSong(ctx, &song, songTags(&song)); err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// PurgeSong permanently deletes a song that is in the trash, along with its
+// artist links, tags, playlist entries, tracks and revisions.
+func (s *Storage) PurgeSong(ctx context.Context, id string) error {
+	songUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	var song models.Song
+	result := s.db.Clauses(clause.Returning{}).Where("id = ? AND is_deleted = true", songUUID).Delete(&song)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return s.invalidateSongs(ctx, []string{id}, songTags(&song)...)
+}
+
+// PurgeTrash permanently deletes songs that were moved to the trash before
+// cutoff, in batches, and returns how many were deleted.
+func (s *Storage) PurgeTrash(ctx context.Context, cutoff time.Time) (int, error) {
+	purged := 0
+	for {
+		var songs []models.Song
+		expired := s.db.Session(&gorm.Session{NewDB: true}).Model(&models.Song{}).Select("id").
+			Where("is_deleted = true AND deleted_at < ?", cutoff).Limit(purgeBatchSize)
+		err := s.db.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "artists"}, {Name: "group_id"}}}).
+			Where("id IN (?)", expired).Delete(&songs).Error
+		if err != nil {
+			return purged, err
+		}
+		if len(songs) == 0 {
+			return purged, nil
+		}
+		ids := make([]string, 0, len(songs))
+		var tags []string
+		for i := range songs {
+			ids = append(ids, songs[i].ID.String())
+			tags = append(tags, songTags(&songs[i])...)
+		}
+		if err := s.invalidateSongs(ctx, ids, tags...); err != nil {
+			return purged, err
+		}
+		purged += len(songs)
+		if len(songs) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
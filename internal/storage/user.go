@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ruziba3vich/music_lib/internal/models"
+	"gorm.io/gorm"
+)
+
+// CreateUser stores a new account with the role it was given, or as a viewer
+// when it has none.
+func (s *Storage) CreateUser(ctx context.Context, user *models.User) error {
+	user.Username = models.NormalizeName(user.Username)
+	if user.Role == "" {
+		user.Role = models.RoleViewer
+	}
+	err := s.db.Create(user).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.ErrDuplicateUser
+	}
+	return err
+}
+
+func (s *Storage) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	if err := s.db.Where("username = ?", models.NormalizeName(username)).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *Storage) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	userUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	var user models.User
+	if err := s.db.Where("id = ?", userUUID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *Storage) GetUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
+	users := []models.User{}
+	if err := s.db.Order("username, id").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (s *Storage) SetUserRole(ctx context.Context, id, role string) (*models.User, error) {
+	userUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateByID(&models.User{}, userUUID, map[string]any{"role": role}, "role"); err != nil {
+		return nil, err
+	}
+	return s.GetUserByID(ctx, id)
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_songs_group_trgm;
+DROP INDEX IF EXISTS idx_songs_name_trgm;
@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_songs_name_trgm ON songs USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_songs_group_trgm ON songs USING GIN ("group" gin_trgm_ops);
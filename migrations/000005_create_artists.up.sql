@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS artists (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    normalized_name TEXT NOT NULL,
+    country TEXT,
+    bio TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_normalized_name ON artists (normalized_name);
+
+CREATE TABLE IF NOT EXISTS song_artists (
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    artist_id UUID NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    position INT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'performer',
+    PRIMARY KEY (song_id, artist_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_artists_artist_id ON song_artists (artist_id);
+
+-- One artist per distinct spelling, ignoring case and surrounding or repeated whitespace.
+INSERT INTO artists (id, name, normalized_name)
+SELECT gen_random_uuid(), min(cleaned), lower(cleaned)
+FROM (
+    SELECT regexp_replace(btrim(artist), '\s+', ' ', 'g') AS cleaned
+    FROM songs, unnest(songs.artists) AS artist
+) AS names
+WHERE cleaned <> ''
+GROUP BY lower(cleaned)
+ON CONFLICT (normalized_name) DO NOTHING;
+
+INSERT INTO song_artists (song_id, artist_id, position, role)
+SELECT credits.song_id, artists.id, min(credits.ordinality) - 1, 'performer'
+FROM (
+    SELECT songs.id AS song_id, lower(regexp_replace(btrim(artist), '\s+', ' ', 'g')) AS normalized_name, ordinality
+    FROM songs, unnest(songs.artists) WITH ORDINALITY AS credit (artist, ordinality)
+) AS credits
+JOIN artists ON artists.normalized_name = credits.normalized_name
+GROUP BY credits.song_id, artists.id
+ON CONFLICT (song_id, artist_id) DO NOTHING;
+
+-- Renumber positions so they are contiguous after duplicates were merged, and
+-- rewrite the denormalized artists array with the canonical spellings.
+UPDATE song_artists
+SET position = ranked.position
+FROM (
+    SELECT song_id, artist_id, row_number() OVER (PARTITION BY song_id ORDER BY position) - 1 AS position
+    FROM song_artists
+) AS ranked
+WHERE song_artists.song_id = ranked.song_id AND song_artists.artist_id = ranked.artist_id;
+
+UPDATE songs
+SET artists = coalesce((
+    SELECT array_agg(artists.name ORDER BY song_artists.position)
+    FROM song_artists
+    JOIN artists ON artists.id = song_artists.artist_id
+    WHERE song_artists.song_id = songs.id
+), '{}');
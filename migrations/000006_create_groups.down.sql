@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_songs_group_id;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
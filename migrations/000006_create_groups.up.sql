@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS groups (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    normalized_name TEXT NOT NULL,
+    formed_on DATE,
+    dissolved_on DATE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_normalized_name ON groups (normalized_name);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    id UUID PRIMARY KEY,
+    group_id UUID NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    artist_id UUID NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    role TEXT,
+    joined_on DATE,
+    left_on DATE,
+    CHECK (left_on IS NULL OR joined_on IS NULL OR left_on > joined_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_group_id ON group_members (group_id);
+CREATE INDEX IF NOT EXISTS idx_group_members_artist_id ON group_members (artist_id);
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES groups (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_songs_group_id ON songs (group_id);
+
+-- One group per distinct spelling, ignoring case and surrounding or repeated whitespace.
+INSERT INTO groups (id, name, normalized_name)
+SELECT gen_random_uuid(), min(cleaned), lower(cleaned)
+FROM (
+    SELECT regexp_replace(btrim("group"), '\s+', ' ', 'g') AS cleaned
+    FROM songs
+) AS names
+WHERE cleaned <> ''
+GROUP BY lower(cleaned)
+ON CONFLICT (normalized_name) DO NOTHING;
+
+UPDATE songs
+SET group_id = groups.id, "group" = groups.name
+FROM groups
+WHERE songs.group_id IS NULL
+    AND groups.normalized_name = lower(regexp_replace(btrim(songs."group"), '\s+', ' ', 'g'));
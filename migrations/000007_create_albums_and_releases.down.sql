@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tracks;
+DROP TABLE IF EXISTS releases;
+DROP TABLE IF EXISTS albums;
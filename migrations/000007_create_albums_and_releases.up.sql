@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS albums (
+    id UUID PRIMARY KEY,
+    title TEXT NOT NULL,
+    group_id UUID REFERENCES groups (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_albums_group_id ON albums (group_id);
+
+CREATE TABLE IF NOT EXISTS releases (
+    id UUID PRIMARY KEY,
+    album_id UUID REFERENCES albums (id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    release_date DATE,
+    label TEXT,
+    format TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_releases_album_id ON releases (album_id);
+
+CREATE TABLE IF NOT EXISTS tracks (
+    id UUID PRIMARY KEY,
+    release_id UUID NOT NULL REFERENCES releases (id) ON DELETE CASCADE,
+    disc_number INT NOT NULL CHECK (disc_number > 0),
+    track_number INT NOT NULL CHECK (track_number > 0),
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tracks_position ON tracks (release_id, disc_number, track_number);
+CREATE INDEX IF NOT EXISTS idx_tracks_song_id ON tracks (song_id);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS genres (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    parent_id UUID REFERENCES genres (id) ON DELETE RESTRICT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_genres_slug ON genres (slug);
+CREATE INDEX IF NOT EXISTS idx_genres_parent_id ON genres (parent_id);
+
+CREATE TABLE IF NOT EXISTS song_genres (
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    genre_id UUID NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_genres_genre_id ON song_genres (genre_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (name);
+
+CREATE TABLE IF NOT EXISTS song_tags (
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_tags_tag_id ON song_tags (tag_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS playlists (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_is_public ON playlists (is_public);
+
+CREATE TABLE IF NOT EXISTS playlist_entries (
+    id UUID PRIMARY KEY,
+    playlist_id UUID NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position DOUBLE PRECISION NOT NULL,
+    added_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_entries_order ON playlist_entries (playlist_id, position);
+CREATE INDEX IF NOT EXISTS idx_playlist_entries_song_id ON playlist_entries (song_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_playlists_owner_id;
+ALTER TABLE playlists DROP COLUMN IF EXISTS owner_id;
+DROP TABLE IF EXISTS users;
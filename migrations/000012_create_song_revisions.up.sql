@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS song_revisions (
+    id UUID PRIMARY KEY,
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    author_user_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    author_api_key_id UUID REFERENCES api_keys (id) ON DELETE SET NULL,
+    state JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_revisions_number ON song_revisions (song_id, number);
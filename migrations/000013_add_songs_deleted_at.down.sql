@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+ALTER TABLE songs DROP COLUMN IF EXISTS deleted_at;
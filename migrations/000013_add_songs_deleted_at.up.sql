@@ -0,0 +1,3 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+UPDATE songs SET deleted_at = NOW() WHERE is_deleted = true AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at);
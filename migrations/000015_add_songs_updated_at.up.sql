@@ -0,0 +1,4 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE songs SET updated_at = COALESCE(created_at, NOW()) WHERE updated_at IS NULL;
+ALTER TABLE songs ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE songs ALTER COLUMN updated_at SET NOT NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS synced_lyrics (
+    song_id UUID PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    lrc TEXT NOT NULL,
+    enhanced BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS song_translations (
+    song_id UUID NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    lines TEXT[] NOT NULL,
+    source_hash TEXT NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (song_id, language)
+);
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_songs_search_vector;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE songs ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce("group", '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(lyrics, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
+
+DROP FUNCTION IF EXISTS song_ts_config(TEXT);
+
+DROP INDEX IF EXISTS idx_songs_language;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS language;
@@ -0,0 +1,29 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_songs_language ON songs (language);
+
+-- song_ts_config picks the text search configuration for a song's language.
+-- Uzbek has no stemmer in PostgreSQL, so it is indexed without one, as are
+-- songs whose language is unknown.
+CREATE OR REPLACE FUNCTION song_ts_config(language TEXT) RETURNS regconfig
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$
+        SELECT CASE language
+            WHEN 'en' THEN 'english'::regconfig
+            WHEN 'ru' THEN 'russian'::regconfig
+            ELSE 'simple'::regconfig
+        END
+    $$;
+
+DROP INDEX IF EXISTS idx_songs_search_vector;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE songs ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector(song_ts_config(language), coalesce(name, '')), 'A') ||
+        setweight(to_tsvector(song_ts_config(language), coalesce("group", '')), 'B') ||
+        setweight(to_tsvector(song_ts_config(language), coalesce(lyrics, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// apiKeyPrefix marks API keys so they are recognisable in logs and secret scanners.
+const apiKeyPrefix = "mlk_"
+
+// GenerateAPIKey returns a new random API key and a short prefix of it that
+// can be shown to identify the key without revealing it.
+func GenerateAPIKey() (key, prefix string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:len(apiKeyPrefix)+6], nil
+}
+
+// HashAPIKey returns the hex SHA-256 of key. Keys carry 256 bits of entropy,
+// so a fast hash is enough and lets keys be looked up by their hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
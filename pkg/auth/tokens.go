@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Token kinds. A refresh token can only be exchanged for a new pair and is
+// never accepted where an access token is expected, and vice versa.
+const (
+	KindAccess  = "access"
+	KindRefresh = "refresh"
+	KindAPIKey  = "api_key"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, expired, of the wrong kind or not signed by this server.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims identify the user a token was issued to.
+type Claims struct {
+	Role string `json:"role"`
+	Kind string `json:"kind"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the ID of the user the token was issued to.
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// APIKeyClaims describes a caller authenticated by an API key rather than a token.
+func APIKeyClaims(keyID uuid.UUID, role string) *Claims {
+	return &Claims{
+		Role:             role,
+		Kind:             KindAPIKey,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: keyID.String()},
+	}
+}
+
+// TokenPair is returned on login and refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Issuer signs and verifies HS256 JWTs.
+type Issuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewIssuer creates an issuer whose access and refresh tokens live for the given durations.
+func NewIssuer(secret []byte, accessTTL, refreshTTL time.Duration) *Issuer {
+	return &Issuer{secret: secret, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// Issue creates an access and a refresh token for the user.
+func (i *Issuer) Issue(userID uuid.UUID, role string) (*TokenPair, error) {
+	access, err := i.sign(userID, role, KindAccess, i.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := i.sign(userID, role, KindRefresh, i.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(i.accessTTL.Seconds()),
+	}, nil
+}
+
+// Parse verifies the token and checks that it is of the expected kind.
+func (i *Issuer) Parse(token, kind string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return i.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Kind != kind {
+		return nil, fmt.Errorf("%w: expected a %s token", ErrInvalidToken, kind)
+	}
+	return &claims, nil
+}
+
+func (i *Issuer) sign(userID uuid.UUID, role, kind string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Role: role,
+		Kind: kind,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+}
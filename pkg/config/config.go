@@ -8,9 +8,13 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// Development is the APP_ENV of a local setup, where the server may start
+// without the secrets it needs in production.
+const Development = "development"
+
 type Config struct {
-	Port, DBHost, DBPort, DBUser, DBPassword, DBName, DBSSLMode, ExternalAPI, RedisHost, RedisPort string
-	RedisTTL                                                                                       int
+	AppEnv, Port, DBHost, DBPort, DBUser, DBPassword, DBName, DBSSLMode, ExternalAPI, RedisHost, RedisPort, CursorSecret, JWTSecret, RateLimitAuth, RateLimitSearch, RateLimitRead, RateLimitWrite, CacheControl, AdminUsername, AdminPassword string
+	RedisTTL, ExternalAPITimeout, ExternalAPIRetries, ExternalAPIBackoff, JWTAccessTTL, JWTRefreshTTL, TrashRetention, TrashPurgeInterval                                                                                                      int
 }
 
 func LoadConfig() *Config {
@@ -19,8 +23,16 @@ func LoadConfig() *Config {
 	}
 
 	redisTTL, _ := strconv.Atoi(getEnv("REDIS_TTL", "3600"))
+	externalAPITimeout, _ := strconv.Atoi(getEnv("EXTERNAL_API_TIMEOUT", "5"))
+	externalAPIRetries, _ := strconv.Atoi(getEnv("EXTERNAL_API_RETRIES", "3"))
+	externalAPIBackoff, _ := strconv.Atoi(getEnv("EXTERNAL_API_BACKOFF_MS", "200"))
+	jwtAccessTTL, _ := strconv.Atoi(getEnv("JWT_ACCESS_TTL_MINUTES", "15"))
+	jwtRefreshTTL, _ := strconv.Atoi(getEnv("JWT_REFRESH_TTL_HOURS", "720"))
+	trashRetention, _ := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	trashPurgeInterval, _ := strconv.Atoi(getEnv("TRASH_PURGE_INTERVAL_MINUTES", "60"))
 
 	config := &Config{
+		AppEnv:      getEnv("APP_ENV", "production"),
 		Port:        getEnv("PORT", "7777"),
 		DBHost:      getEnv("DB_HOST", "localhost"),
 		DBPort:      getEnv("DB_PORT", "5432"),
@@ -32,6 +44,29 @@ func LoadConfig() *Config {
 		RedisTTL:    redisTTL,
 		RedisHost:   getEnv("REDIS_HOST", "localhost"),
 		RedisPort:   getEnv("REDIS_PORT", "6379"),
+
+		CursorSecret: getEnv("CURSOR_SECRET", ""),
+
+		JWTSecret:     getEnv("JWT_SECRET", ""),
+		JWTAccessTTL:  jwtAccessTTL,
+		JWTRefreshTTL: jwtRefreshTTL,
+
+		AdminUsername: getEnv("ADMIN_USERNAME", ""),
+		AdminPassword: getEnv("ADMIN_PASSWORD", ""),
+
+		RateLimitAuth:   getEnv("RATE_LIMIT_AUTH", "10/1m"),
+		RateLimitSearch: getEnv("RATE_LIMIT_SEARCH", "60/1m"),
+		RateLimitRead:   getEnv("RATE_LIMIT_READ", "300/1m"),
+		RateLimitWrite:  getEnv("RATE_LIMIT_WRITE", "60/1m"),
+
+		CacheControl: getEnv("CACHE_CONTROL", "private, no-cache"),
+
+		TrashRetention:     trashRetention,
+		TrashPurgeInterval: trashPurgeInterval,
+
+		ExternalAPITimeout: externalAPITimeout,
+		ExternalAPIRetries: externalAPIRetries,
+		ExternalAPIBackoff: externalAPIBackoff,
 	}
 
 	return config
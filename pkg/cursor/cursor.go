@@ -0,0 +1,59 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for cursors that are malformed or were not signed by this server.
+var ErrInvalid = errors.New("invalid cursor")
+
+// Codec turns pagination positions into opaque, HMAC-signed tokens and back.
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec creates a codec that signs cursors with secret.
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+// Encode serializes v and appends its signature.
+func (c *Codec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded)), nil
+}
+
+// Decode verifies the token's signature and unmarshals its payload into v.
+func (c *Codec) Decode(token string, v any) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, c.sign(encoded)) {
+		return ErrInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func (c *Codec) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,80 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type position struct {
+	Key string `json:"k"`
+	ID  int    `json:"i"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	token, err := codec.Encode(position{Key: "Bohemian Rhapsody", ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(token, "+/= ") {
+		t.Errorf("token %q is not URL safe", token)
+	}
+	var got position
+	if err := codec.Decode(token, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != (position{Key: "Bohemian Rhapsody", ID: 7}) {
+		t.Errorf("Decode = %+v", got)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	token, err := codec.Encode(position{Key: "a", ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+	forged, err := NewCodec([]byte("secret")).Encode(position{Key: "b", ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	foreign, err := NewCodec([]byte("other secret")).Encode(position{Key: "a", ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+
+	tests := map[string]string{
+		"empty":                "",
+		"no signature":         payload,
+		"empty signature":      payload + ".",
+		"tampered payload":     forgedPayload + "." + signature,
+		"tampered signature":   payload + "." + string(flipped),
+		"signature not base64": payload + ".!!!",
+		"foreign secret":       foreign,
+		"extra dot":            token + ".x",
+		"payload not JSON":     "bm90IGpzb24." + base64.RawURLEncoding.EncodeToString(codec.sign("bm90IGpzb24")),
+		"payload not base64":   "!!!." + base64.RawURLEncoding.EncodeToString(codec.sign("!!!")),
+	}
+	for name, token := range tests {
+		var got position
+		if err := codec.Decode(token, &got); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Decode(%q) error = %v, want ErrInvalid", name, token, err)
+		}
+	}
+
+	// A signed payload of the wrong shape is rejected too.
+	wrong, err := codec.Encode([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got position
+	if err := codec.Decode(wrong, &got); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Decode of another type error = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,348 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// assertJSON fails the test unless got and want hold the same JSON value.
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue any
+	if err := decode(got, &gotValue); err != nil {
+		t.Fatalf("result %s is not JSON: %v", got, err)
+	}
+	if err := decode([]byte(want), &wantValue); err != nil {
+		t.Fatalf("expectation %s is not JSON: %v", want, err)
+	}
+	if !equal(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// TestApplyRFC6902 runs the examples of RFC 6902 appendix A.
+func TestApplyRFC6902(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+		err   error
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+			want:  `{"foo": "bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:  `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "A.8 testing a value: success",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			want: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:  "A.9 testing a value: error",
+			doc:   `{"baz": "qux"}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:  `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:  "A.12 adding to a nonexistent target",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			err:   ErrUnprocessable,
+		},
+		{
+			name: "A.14 ~ escape ordering",
+			doc:  `{"/": 9, "~1": 10}`,
+			patch: `[
+				{"op": "test", "path": "/~01", "value": 10}
+			]`,
+			want: `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:  "A.15 comparing strings and numbers",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:  `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyNullValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace with null",
+			doc:   `{"name": "Uprising", "group_id": "7d5f3a52-3b8e-4c1e-9d2a-6f4b8c0e1a23"}`,
+			patch: `[{"op": "replace", "path": "/group_id", "value": null}]`,
+			want:  `{"name": "Uprising", "group_id": null}`,
+		},
+		{
+			name:  "add null",
+			doc:   `{"name": "Uprising"}`,
+			patch: `[{"op": "add", "path": "/group_id", "value": null}]`,
+			want:  `{"name": "Uprising", "group_id": null}`,
+		},
+		{
+			name:  "test null",
+			doc:   `{"group_id": null}`,
+			patch: `[{"op": "test", "path": "/group_id", "value": null}]`,
+			want:  `{"group_id": null}`,
+		},
+		{
+			name:  "add null to an array",
+			doc:   `[1]`,
+			patch: `[{"op": "add", "path": "/0", "value": null}]`,
+			want:  `[null, 1]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+
+	if _, err := Apply([]byte(`{"group_id": "x"}`), []byte(`[{"op": "test", "path": "/group_id", "value": null}]`)); !errors.Is(err, ErrTestFailed) {
+		t.Errorf("test null against a string: err = %v, want %v", err, ErrTestFailed)
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace the whole document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "", "value": {"baz": 1}}]`,
+			want:  `{"baz": 1}`,
+		},
+		{
+			name:  "copy is independent of its source",
+			doc:   `{"a": {"b": 1}}`,
+			patch: `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "replace", "path": "/c/b", "value": 2}]`,
+			want:  `{"a": {"b": 1}, "c": {"b": 2}}`,
+		},
+		{
+			name:  "test compares numbers by value",
+			doc:   `{"n": 1}`,
+			patch: `[{"op": "test", "path": "/n", "value": 1.0}]`,
+			want:  `{"n": 1}`,
+		},
+		{
+			name:  "test ignores member order",
+			doc:   `{"o": {"a": 1, "b": [true, null]}}`,
+			patch: `[{"op": "test", "path": "/o", "value": {"b": [true, null], "a": 1}}]`,
+			want:  `{"o": {"a": 1, "b": [true, null]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyKeepsNumbers(t *testing.T) {
+	got, err := Apply([]byte(`{"n": 12345678901234567890, "f": 0.1}`), []byte(`[{"op": "add", "path": "/m", "value": 1}]`))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	for _, number := range []string{"12345678901234567890", "0.1"} {
+		if !bytes.Contains(got, []byte(number)) {
+			t.Errorf("got %s, want %s unchanged", got, number)
+		}
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		err   error
+	}{
+		{name: "not an array", doc: `{}`, patch: `{"op": "add", "path": "/a", "value": 1}`, err: ErrMalformed},
+		{name: "operation is not an object", doc: `{}`, patch: `["add"]`, err: ErrMalformed},
+		{name: "missing value", doc: `{}`, patch: `[{"op": "add", "path": "/a"}]`, err: ErrMalformed},
+		{name: "missing path", doc: `{}`, patch: `[{"op": "remove"}]`, err: ErrMalformed},
+		{name: "missing from", doc: `{"a": 1}`, patch: `[{"op": "move", "path": "/b"}]`, err: ErrMalformed},
+		{name: "missing op", doc: `{}`, patch: `[{"path": "/a", "value": 1}]`, err: ErrMalformed},
+		{name: "null path", doc: `{}`, patch: `[{"op": "remove", "path": null}]`, err: ErrMalformed},
+		{name: "unknown op", doc: `{}`, patch: `[{"op": "merge", "path": "/a", "value": 1}]`, err: ErrMalformed},
+		{name: "path without leading slash", doc: `{"a": 1}`, patch: `[{"op": "remove", "path": "a"}]`, err: ErrMalformed},
+		{name: "remove a missing member", doc: `{}`, patch: `[{"op": "remove", "path": "/a"}]`, err: ErrUnprocessable},
+		{name: "replace a missing member", doc: `{}`, patch: `[{"op": "replace", "path": "/a", "value": 1}]`, err: ErrUnprocessable},
+		{name: "array index out of range", doc: `[1]`, patch: `[{"op": "add", "path": "/2", "value": 1}]`, err: ErrUnprocessable},
+		{name: "array index with leading zero", doc: `[1, 2]`, patch: `[{"op": "remove", "path": "/01"}]`, err: ErrUnprocessable},
+		{name: "move into its own child", doc: `{"a": {"b": 1}}`, patch: `[{"op": "move", "from": "/a", "path": "/a/c"}]`, err: ErrUnprocessable},
+		{name: "remove the whole document", doc: `{}`, patch: `[{"op": "remove", "path": ""}]`, err: ErrUnprocessable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Apply = %s, %v, want %v", got, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	doc := []byte(`{"a": 1}`)
+	_, err := Apply(doc, []byte(`[{"op": "replace", "path": "/a", "value": 2}, {"op": "test", "path": "/a", "value": 3}]`))
+	if !errors.Is(err, ErrTestFailed) {
+		t.Fatalf("err = %v, want %v", err, ErrTestFailed)
+	}
+	if string(doc) != `{"a": 1}` {
+		t.Errorf("doc = %s, want it unchanged", doc)
+	}
+}
+
+func TestOperationUnmarshalKeepsNull(t *testing.T) {
+	var op Operation
+	if err := json.Unmarshal([]byte(`{"op": "replace", "path": "/group_id", "value": null}`), &op); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if string(op.Value) != "null" {
+		t.Errorf("Value = %q, want null", op.Value)
+	}
+}
+
+// TestMergePatchRFC7396 runs the examples of RFC 7396 appendix A.
+func TestMergePatchRFC7396(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergePatchMalformed(t *testing.T) {
+	for _, patch := range []string{``, `{"a":`, `{"a":1} {"b":2}`} {
+		if _, err := MergePatch([]byte(`{}`), []byte(patch)); !errors.Is(err, ErrMalformed) {
+			t.Errorf("MergePatch(%q): err = %v, want %v", patch, err, ErrMalformed)
+		}
+	}
+}
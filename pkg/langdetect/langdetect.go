@@ -0,0 +1,148 @@
+// Package langdetect guesses the language of a text from its character
+// trigrams. It works offline: the profiles are built from the sample texts
+// embedded in corpus, one per language and script.
+package langdetect
+
+import (
+	"embed"
+	"math"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Detected languages, as BCP 47 tags.
+const (
+	English = "en"
+	Russian = "ru"
+	Uzbek   = "uz"
+)
+
+// MinLetters is the number of letters below which a text is too short to tell.
+const MinLetters = 10
+
+// maxTrigrams bounds the work done for long texts; the start of a song is enough.
+const maxTrigrams = 4000
+
+//go:embed corpus/*.txt
+var corpus embed.FS
+
+// sources maps corpus files to the language and script they are written in.
+// Uzbek is written in both Latin and Cyrillic.
+var sources = []struct {
+	file     string
+	language string
+	script   *unicode.RangeTable
+}{
+	{"en.txt", English, unicode.Latin},
+	{"ru.txt", Russian, unicode.Cyrillic},
+	{"uz.txt", Uzbek, unicode.Latin},
+	{"uz-cyrl.txt", Uzbek, unicode.Cyrillic},
+}
+
+type profile struct {
+	language string
+	script   *unicode.RangeTable
+	counts   map[string]int
+	total    int
+}
+
+var (
+	loadOnce sync.Once
+	profiles []*profile
+)
+
+func load() {
+	for _, source := range sources {
+		data, err := corpus.ReadFile(path.Join("corpus", source.file))
+		if err != nil {
+			panic("langdetect: " + err.Error())
+		}
+		p := &profile{language: source.language, script: source.script, counts: map[string]int{}}
+		for _, trigram := range trigrams(string(data)) {
+			p.counts[trigram]++
+			p.total++
+		}
+		profiles = append(profiles, p)
+	}
+}
+
+// Detect returns the language text is most likely written in: English,
+// Russian or Uzbek. It returns "" when the text has fewer than MinLetters
+// letters or is in a script none of the languages use.
+func Detect(text string) string {
+	loadOnce.Do(load)
+
+	text = normalize(text)
+	latin, cyrillic := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		}
+	}
+	if latin+cyrillic < MinLetters {
+		return ""
+	}
+	script := unicode.Latin
+	if cyrillic > latin {
+		script = unicode.Cyrillic
+	}
+
+	grams := trigrams(text)
+	if len(grams) > maxTrigrams {
+		grams = grams[:maxTrigrams]
+	}
+	best, bestScore := "", math.Inf(-1)
+	for _, p := range profiles {
+		if p.script != script {
+			continue
+		}
+		if score := p.score(grams); score > bestScore {
+			best, bestScore = p.language, score
+		}
+	}
+	return best
+}
+
+// score is the log-likelihood of the trigrams under the profile, with add-one
+// smoothing for trigrams the corpus never had.
+func (p *profile) score(grams []string) float64 {
+	denominator := math.Log(float64(p.total + len(p.counts) + 1))
+	score := 0.0
+	for _, gram := range grams {
+		score += math.Log(float64(p.counts[gram]+1)) - denominator
+	}
+	return score
+}
+
+// apostrophes are the characters Uzbek Latin writes o‘ and g‘ with, folded to
+// a plain apostrophe.
+var apostrophes = strings.NewReplacer("‘", "'", "’", "'", "ʻ", "'", "ʼ", "'", "`", "'")
+
+func normalize(text string) string {
+	return strings.ToLower(apostrophes.Replace(text))
+}
+
+// trigrams splits normalized text into words and returns the trigrams of each
+// word padded with spaces, so word beginnings and endings count too.
+func trigrams(text string) []string {
+	var grams []string
+	words := strings.FieldsFunc(normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if word == "" {
+			continue
+		}
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+3]))
+		}
+	}
+	return grams
+}
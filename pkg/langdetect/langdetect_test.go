@@ -0,0 +1,54 @@
+package langdetect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "Sing it loud, sing it bright,\nFeel the rhythm through the night.", English},
+		{"russian", "Пой громко, пой ярко,\nЧувствуй ритм всю ночь.", Russian},
+		{"uzbek cyrillic", "Баланд куйла, ёруғ куйла,\nТун бўйи ритмни ҳис қил.", Uzbek},
+		{"uzbek latin, turned comma", "O‘zbekiston go‘zal yurtim, bog‘larida gullar ochiladi", Uzbek},
+		{"uzbek latin, right quote", "O’zbekiston go’zal yurtim, bog’larida gullar ochiladi", Uzbek},
+		{"uzbek latin, modifier letter", "Oʻzbekiston goʻzal yurtim, bogʻlarida gullar ochiladi", Uzbek},
+		{"uzbek latin, apostrophe", "O'zbekiston go'zal yurtim, bog'larida gullar ochiladi", Uzbek},
+		{"uzbek latin, backtick", "O`zbekiston go`zal yurtim, bog`larida gullar ochiladi", Uzbek},
+		{"mostly russian with english words", "Я люблю тебя, baby, и эту ночь до самого утра", Russian},
+		{"mostly english with russian words", "I said da and nyet, and then she said спасибо to the whole crowd tonight", English},
+		{"too short", "la la la", ""},
+		{"digits and punctuation", "1, 2, 3, 4 — 5!!! 678 910", ""},
+		{"other script", "Αυτό είναι ένα ελληνικό τραγούδι για τη νύχτα", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.text); got != tt.want {
+			t.Errorf("%s: Detect(%q) = %q, want %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDetectMinLetters(t *testing.T) {
+	// Spaces, digits and punctuation do not count towards MinLetters.
+	short := strings.Repeat("a", MinLetters-1) + " 12345, !?"
+	if got := Detect(short); got != "" {
+		t.Errorf("Detect(%q) = %q, want \"\" below MinLetters", short, got)
+	}
+	long := strings.Repeat("a", MinLetters) + " 12345"
+	if got := Detect(long); got == "" {
+		t.Errorf("Detect(%q) = \"\", want a language at MinLetters", long)
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	got := trigrams("Go‘zal, O'K!")
+	want := []string{" go", "go'", "o'z", "'za", "zal", "al ", " o'", "o'k", "'k "}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("trigrams = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,360 @@
+package lrc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp is a position in a song. It is written as seconds in JSON.
+type Timestamp time.Duration
+
+// Seconds converts seconds, such as a query parameter, to a timestamp.
+func Seconds(seconds float64) Timestamp {
+	return Timestamp(time.Duration(seconds * float64(time.Second)).Round(time.Millisecond))
+}
+
+// String formats the timestamp as mm:ss.xx, or mm:ss.xxx when it has milliseconds.
+func (t Timestamp) String() string {
+	ms := time.Duration(t).Milliseconds()
+	minutes, seconds, millis := ms/60000, ms/1000%60, ms%1000
+	if millis%10 == 0 {
+		return fmt.Sprintf("%02d:%02d.%02d", minutes, seconds, millis/10)
+	}
+	return fmt.Sprintf("%02d:%02d.%03d", minutes, seconds, millis)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(time.Duration(t).Seconds(), 'f', -1, 64)), nil
+}
+
+// Word is a word of an enhanced LRC line with the time it is sung.
+type Word struct {
+	Time Timestamp `json:"time" swaggertype:"number"`
+	Text string    `json:"text"`
+}
+
+// Line is a lyric line with the time it starts and, in enhanced LRC, the
+// time its last word ends. Lines without text mark instrumental breaks.
+type Line struct {
+	Time  Timestamp  `json:"time" swaggertype:"number"`
+	End   *Timestamp `json:"end,omitempty" swaggertype:"number"`
+	Text  string     `json:"text"`
+	Words []Word     `json:"words,omitempty"`
+}
+
+// Lyrics are time-synced lyrics in playing order, with their ID tags such as
+// ar (artist) and ti (title).
+type Lyrics struct {
+	Tags  map[string]string `json:"tags,omitempty"`
+	Lines []Line            `json:"lines"`
+}
+
+// ParseError is a line of an LRC file that is malformed or out of order.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+var (
+	// lineTag matches a [mm:ss], [mm:ss.xx] or [mm:ss.xxx] time tag.
+	lineTag = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	// wordTag matches an enhanced LRC <mm:ss.xx> word tag.
+	wordTag = regexp.MustCompile(`<(\d+):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+	// idTag matches an [ar:Artist] style ID tag.
+	idTag = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+)
+
+// tagOrder is the order ID tags are written in; others follow alphabetically.
+var tagOrder = []string{"ti", "ar", "al", "au", "by", "length", "offset", "re", "ve"}
+
+// Parse reads LRC or enhanced LRC. Lines must be in playing order, except
+// lines carrying several time tags, which are repeated at each of them. Word
+// tags must be in order and fall within their line. An offset tag, in
+// milliseconds, is applied to every timestamp and then dropped, so formatting
+// the lyrics does not apply it twice; a positive offset makes lines earlier.
+func Parse(text string) (*Lyrics, error) {
+	lyrics := &Lyrics{Tags: map[string]string{}, Lines: []Line{}}
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var last Timestamp
+	var offset int
+	for n, raw := range strings.Split(text, "\n") {
+		number := n + 1
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		var times []Timestamp
+		rest := raw
+		for {
+			match := lineTag.FindStringSubmatch(rest)
+			if match == nil {
+				break
+			}
+			t, err := parseTime(match[1:])
+			if err != nil {
+				return nil, &ParseError{Line: number, Msg: err.Error()}
+			}
+			times = append(times, t)
+			rest = rest[len(match[0]):]
+		}
+		if len(times) == 0 {
+			if match := idTag.FindStringSubmatch(raw); match != nil {
+				key, value := strings.ToLower(match[1]), strings.TrimSpace(match[2])
+				if key == "offset" {
+					var err error
+					if offset, err = strconv.Atoi(value); err != nil {
+						return nil, &ParseError{Line: number, Msg: fmt.Sprintf("offset %q is not a number of milliseconds", value)}
+					}
+					if limit := maxMinutes * int(time.Minute/time.Millisecond); offset > limit || offset < -limit {
+						return nil, &ParseError{Line: number, Msg: fmt.Sprintf("offset %q is out of range", value)}
+					}
+					continue
+				}
+				lyrics.Tags[key] = value
+				continue
+			}
+			return nil, &ParseError{Line: number, Msg: "line has no time tag"}
+		}
+
+		line, err := parseWords(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, &ParseError{Line: number, Msg: err.Error()}
+		}
+		if len(times) > 1 {
+			if line.Words != nil {
+				return nil, &ParseError{Line: number, Msg: "a line with word tags can have only one time tag"}
+			}
+			for i := 1; i < len(times); i++ {
+				if times[i] < times[i-1] {
+					return nil, &ParseError{Line: number, Msg: "time tags are out of order"}
+				}
+			}
+		} else {
+			if times[0] < last {
+				return nil, &ParseError{Line: number, Msg: fmt.Sprintf("line starts at %s, before the previous line at %s", times[0], last)}
+			}
+			last = times[0]
+			if len(line.Words) > 0 && line.Words[0].Time < times[0] {
+				return nil, &ParseError{Line: number, Msg: fmt.Sprintf("word at %s comes before its line at %s", line.Words[0].Time, times[0])}
+			}
+		}
+		for _, t := range times {
+			line.Time = t
+			lyrics.Lines = append(lyrics.Lines, line)
+		}
+	}
+
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].Time < lyrics.Lines[j].Time
+	})
+	for i := 0; i+1 < len(lyrics.Lines); i++ {
+		line, next := lyrics.Lines[i], lyrics.Lines[i+1]
+		if len(line.Words) > 0 && line.lastTime() > next.Time {
+			return nil, &ParseError{Line: 0, Msg: fmt.Sprintf("words of the line at %s run past the next line at %s", line.Time, next.Time)}
+		}
+	}
+	if len(lyrics.Lines) == 0 {
+		return nil, &ParseError{Line: 0, Msg: "no timed lines"}
+	}
+	if offset != 0 {
+		lyrics.shift(-Timestamp(time.Duration(offset) * time.Millisecond))
+	}
+	return lyrics, nil
+}
+
+// shift moves every timestamp by d, stopping at the start of the song. Order
+// is kept, since times that would fall before it all become zero.
+func (l *Lyrics) shift(d Timestamp) {
+	move := func(t Timestamp) Timestamp { return max(t+d, 0) }
+	for i := range l.Lines {
+		line := &l.Lines[i]
+		line.Time = move(line.Time)
+		if line.End != nil {
+			end := move(*line.End)
+			line.End = &end
+		}
+		if line.Words != nil {
+			words := make([]Word, len(line.Words))
+			for j, word := range line.Words {
+				words[j] = Word{Time: move(word.Time), Text: word.Text}
+			}
+			line.Words = words
+		}
+	}
+}
+
+// parseWords reads the text of a line, splitting it into words if it has word tags.
+func parseWords(text string) (Line, error) {
+	tags := wordTag.FindAllStringSubmatchIndex(text, -1)
+	if len(tags) == 0 {
+		return Line{Text: text}, nil
+	}
+	if tags[0][0] != 0 && strings.TrimSpace(text[:tags[0][0]]) != "" {
+		return Line{}, fmt.Errorf("text before the first word tag")
+	}
+
+	line := Line{Words: []Word{}}
+	var parts []string
+	for i, tag := range tags {
+		end := len(text)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		t, err := parseTime([]string{text[tag[2]:tag[3]], text[tag[4]:tag[5]], submatch(text, tag, 3)})
+		if err != nil {
+			return Line{}, err
+		}
+		if len(line.Words) > 0 && t < line.Words[len(line.Words)-1].Time {
+			return Line{}, fmt.Errorf("word tag %s is out of order", t)
+		}
+		word := strings.TrimSpace(text[tag[1]:end])
+		if word == "" {
+			// A tag after the last word marks when it ends.
+			if i == len(tags)-1 && len(line.Words) > 0 {
+				line.End = &t
+			}
+			continue
+		}
+		line.Words = append(line.Words, Word{Time: t, Text: word})
+		parts = append(parts, word)
+	}
+	line.Text = strings.Join(parts, " ")
+	return line, nil
+}
+
+func submatch(text string, indexes []int, group int) string {
+	if indexes[2*group] < 0 {
+		return ""
+	}
+	return text[indexes[2*group]:indexes[2*group+1]]
+}
+
+// maxMinutes bounds the minutes of a time tag, and the size of an offset, far
+// above any song's length, so timestamps cannot overflow.
+const maxMinutes = 10000
+
+// parseTime converts minutes, seconds and an optional fraction to a timestamp.
+func parseTime(parts []string) (Timestamp, error) {
+	minutes, err := strconv.Atoi(parts[0])
+	if err != nil || minutes > maxMinutes {
+		return 0, fmt.Errorf("minutes %q are out of range", parts[0])
+	}
+	seconds, err := strconv.Atoi(parts[1])
+	if err != nil || seconds >= 60 {
+		return 0, fmt.Errorf("seconds %q are out of range", parts[1])
+	}
+	d := time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if fraction := parts[2]; fraction != "" {
+		value, err := strconv.Atoi(fraction)
+		if err != nil {
+			return 0, fmt.Errorf("fraction %q is not a number", fraction)
+		}
+		for i := len(fraction); i < 3; i++ {
+			value *= 10
+		}
+		d += time.Duration(value) * time.Millisecond
+	}
+	return Timestamp(d), nil
+}
+
+// Format writes the lyrics as LRC, with word tags unless plain is set.
+func (l *Lyrics) Format(plain bool) string {
+	var b strings.Builder
+	for _, key := range l.tagKeys() {
+		fmt.Fprintf(&b, "[%s:%s]\n", key, l.Tags[key])
+	}
+	for _, line := range l.Lines {
+		fmt.Fprintf(&b, "[%s]", line.Time)
+		if plain || len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		} else {
+			for i, word := range line.Words {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				fmt.Fprintf(&b, "<%s>%s", word.Time, word.Text)
+			}
+			if line.End != nil {
+				fmt.Fprintf(&b, " <%s>", *line.End)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// lastTime is the latest word timing of the line.
+func (l Line) lastTime() Timestamp {
+	if l.End != nil {
+		return *l.End
+	}
+	return l.Words[len(l.Words)-1].Time
+}
+
+// Enhanced reports whether any line has word timings.
+func (l *Lyrics) Enhanced() bool {
+	for _, line := range l.Lines {
+		if len(line.Words) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Plain returns the text of the lyrics. Instrumental breaks become blank
+// lines, which separate sections.
+func (l *Lyrics) Plain() string {
+	lines := make([]string, 0, len(l.Lines))
+	for _, line := range l.Lines {
+		if line.Text == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line.Text)
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// At returns the index of the line and of its word being sung at t, or -1
+// for either when t is before the first line or the line's first word.
+func (l *Lyrics) At(t Timestamp) (line, word int) {
+	line = sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].Time > t }) - 1
+	if line < 0 {
+		return -1, -1
+	}
+	words := l.Lines[line].Words
+	word = sort.Search(len(words), func(i int) bool { return words[i].Time > t }) - 1
+	return line, word
+}
+
+func (l *Lyrics) tagKeys() []string {
+	keys := make([]string, 0, len(l.Tags))
+	known := make(map[string]bool, len(tagOrder))
+	for _, key := range tagOrder {
+		known[key] = true
+		if _, ok := l.Tags[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	var others []string
+	for key := range l.Tags {
+		if !known[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	return append(keys, others...)
+}
@@ -0,0 +1,182 @@
+package lrc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func ms(n int) Timestamp {
+	return Timestamp(time.Duration(n) * time.Millisecond)
+}
+
+func TestParse(t *testing.T) {
+	lyrics, err := Parse("\ufeff[ti:Epic Song]\r\n[00:12.00]<00:12.00>This <00:12.40>is <00:12.90>the start <00:14.5>\r\n[00:15.5]A melody\r\n[00:18.00]\r\n[00:20.00][01:10.00]Sing it loud\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lyrics.Tags["ti"]; got != "Epic Song" {
+		t.Errorf("ti = %q, want %q", got, "Epic Song")
+	}
+
+	want := []struct {
+		time Timestamp
+		text string
+	}{
+		{ms(12000), "This is the start"},
+		{ms(15500), "A melody"},
+		{ms(18000), ""},
+		{ms(20000), "Sing it loud"},
+		{ms(70000), "Sing it loud"},
+	}
+	if len(lyrics.Lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lyrics.Lines), len(want))
+	}
+	for i, w := range want {
+		if line := lyrics.Lines[i]; line.Time != w.time || line.Text != w.text {
+			t.Errorf("line %d = %s %q, want %s %q", i, line.Time, line.Text, w.time, w.text)
+		}
+	}
+
+	words := lyrics.Lines[0].Words
+	if len(words) != 3 || words[2].Text != "the start" || words[1].Time != ms(12400) || words[1].Text != "is" {
+		t.Errorf("words = %+v", words)
+	}
+	if end := lyrics.Lines[0].End; end == nil || *end != ms(14500) {
+		t.Errorf("End = %v, want 00:14.50", end)
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		offset string
+		want   []Timestamp
+	}{
+		{offset: "+500", want: []Timestamp{ms(500), ms(11500), ms(11900)}},
+		{offset: "-250", want: []Timestamp{ms(1250), ms(12250), ms(12650)}},
+		// Times pushed before the start of the song stop there.
+		{offset: "2000", want: []Timestamp{0, ms(10000), ms(10400)}},
+	}
+	for _, tt := range tests {
+		lyrics, err := Parse("[offset:" + tt.offset + "]\n[00:01.00]Intro\n[00:12.00]<00:12.00>This <00:12.40>is\n")
+		if err != nil {
+			t.Errorf("offset %s: %v", tt.offset, err)
+			continue
+		}
+		got := []Timestamp{lyrics.Lines[0].Time, lyrics.Lines[1].Time, lyrics.Lines[1].Words[1].Time}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("offset %s: times = %v, want %v", tt.offset, got, tt.want)
+				break
+			}
+		}
+		if _, ok := lyrics.Tags["offset"]; ok {
+			t.Errorf("offset %s: tag kept, want it applied and dropped", tt.offset)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	text := "[ti:Song]\n[ar:Artist]\n[00:01.50]<00:01.50>One <00:02.125>two <00:03.00>\n[00:05.00]\n[01:00.00]Three\n"
+	lyrics, err := Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lyrics.Format(false); got != text {
+		t.Errorf("Format =\n%s\nwant\n%s", got, text)
+	}
+	if got, want := lyrics.Format(true), "[ti:Song]\n[ar:Artist]\n[00:01.50]One two\n[00:05.00]\n[01:00.00]Three\n"; got != want {
+		t.Errorf("Format(plain) =\n%s\nwant\n%s", got, want)
+	}
+
+	shifted, err := Parse("[offset:1000]\n" + text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(shifted.Format(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := again.Lines[0].Time, ms(500); got != want {
+		t.Errorf("time after a round trip = %s, want %s, with the offset applied once", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line int
+	}{
+		{name: "no time tag", text: "[00:01.00]One\nTwo\n", line: 2},
+		{name: "seconds out of range", text: "[00:75]One\n", line: 1},
+		{name: "word seconds out of range", text: "[00:01.00]<00:01.00>One <00:60.00>two\n", line: 1},
+		{name: "minutes overflow int", text: "[00:01.00]One\n[99999999999999999999:00]Two\n", line: 2},
+		{name: "minutes overflow a duration", text: "[3000000000:00]One\n", line: 1},
+		{name: "minutes past the bound", text: "[10001:00]One\n", line: 1},
+		{name: "word minutes overflow", text: "[00:01.00]<00:01.00>One <99999999999999999999:00>two\n", line: 1},
+		{name: "bad offset", text: "[offset:soon]\n[00:01.00]One\n", line: 1},
+		{name: "offset overflows a duration", text: "[offset:9223372036854775]\n[00:01.00]One\n", line: 1},
+		{name: "out of order", text: "[00:05.00]One\n[00:01.00]Two\n", line: 2},
+		{name: "word before line", text: "[00:05.00]<00:04.00>One\n", line: 1},
+		{name: "words out of order", text: "[00:05.00]<00:06.00>One <00:05.50>two\n", line: 1},
+		{name: "text before words", text: "[00:05.00]One <00:06.00>two\n", line: 1},
+		{name: "words run past the next line", text: "[00:05.00]<00:05.00>One <00:07.00>two\n[00:06.00]Three\n", line: 0},
+		{name: "no timed lines", text: "[ti:Song]\n", line: 0},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.text)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: error = %v, want a ParseError", tt.name, err)
+			continue
+		}
+		if parseErr.Line != tt.line {
+			t.Errorf("%s: error on line %d, want %d: %v", tt.name, parseErr.Line, tt.line, err)
+		}
+	}
+}
+
+func TestParseLongTimes(t *testing.T) {
+	lyrics, err := Parse("[offset:-600000000]\n[10000:59.999]One\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Timestamp(10000*time.Minute + 59999*time.Millisecond + 600000000*time.Millisecond)
+	if got := lyrics.Lines[0].Time; got != want {
+		t.Errorf("time = %s, want %s", got, want)
+	}
+}
+
+func TestAt(t *testing.T) {
+	lyrics, err := Parse("[00:10.00]<00:10.00>One <00:11.00>two\n[00:20.00]Three\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at         Timestamp
+		line, word int
+	}{
+		{at: ms(5000), line: -1, word: -1},
+		{at: ms(10000), line: 0, word: 0},
+		{at: ms(11500), line: 0, word: 1},
+		{at: ms(25000), line: 1, word: -1},
+	}
+	for _, tt := range tests {
+		if line, word := lyrics.At(tt.at); line != tt.line || word != tt.word {
+			t.Errorf("At(%s) = %d, %d, want %d, %d", tt.at, line, word, tt.line, tt.word)
+		}
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	if got, want := Seconds(83.2), ms(83200); got != want {
+		t.Errorf("Seconds(83.2) = %s, want %s", got, want)
+	}
+	if got, want := ms(83200).String(), "01:23.20"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+	if got, want := ms(83205).String(), "01:23.205"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy allows Limit requests in any Window-long span of time.
+type Policy struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ParsePolicy parses "<limit>/<window>", e.g. "100/1m". An empty string means no limit.
+func ParsePolicy(s string) (Policy, error) {
+	if s == "" {
+		return Policy{}, nil
+	}
+	count, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("invalid rate limit %q, expected <limit>/<window>", s)
+	}
+	limit, err := strconv.Atoi(count)
+	if err != nil || limit <= 0 {
+		return Policy{}, fmt.Errorf("invalid rate limit %q: limit must be a positive integer", s)
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return Policy{}, fmt.Errorf("invalid rate limit %q: window must be a positive duration", s)
+	}
+	return Policy{Limit: limit, Window: duration}, nil
+}
+
+// Result describes the state of a client's window after a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the window has room for the full limit again.
+	Reset time.Duration
+	// RetryAfter is how long a rejected client has to wait before its next request can succeed.
+	RetryAfter time.Duration
+}
+
+// slidingWindow keeps one sorted-set member per accepted request, scored by
+// its time in microseconds, and counts the members inside the window. Time is
+// read from Redis so every replica agrees on it. When record is 0 the window
+// is only inspected, and nothing is added to it.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local record = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	allowed = 1
+	if record == 1 then
+		redis.call('ZADD', key, now, now .. '-' .. math.random(1000000))
+		count = count + 1
+	end
+end
+redis.call('PEXPIRE', key, math.ceil(window / 1000))
+
+local oldest = tonumber(redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')[2] or now)
+local newest = tonumber(redis.call('ZRANGE', key, -1, -1, 'WITHSCORES')[2] or now)
+return {allowed, limit - count, newest + window - now, oldest + window - now}
+`)
+
+// Limiter enforces per-group policies with counters stored in Redis, so the
+// limits hold across replicas.
+type Limiter struct {
+	client   *redis.Client
+	policies map[string]Policy
+}
+
+// NewLimiter creates a limiter with a policy per route group. Groups without a
+// policy, or with a zero one, are not limited.
+func NewLimiter(client *redis.Client, policies map[string]Policy) *Limiter {
+	return &Limiter{client: client, policies: policies}
+}
+
+// Allow records a request by client in group and reports whether it is within the limit.
+// ok is false when the group is not limited.
+func (l *Limiter) Allow(ctx context.Context, group, client string) (result Result, ok bool, err error) {
+	return l.run(ctx, group, client, true)
+}
+
+// Check reports whether client has room for another request in group without
+// recording one, so callers can count only some requests, such as failed ones.
+// ok is false when the group is not limited.
+func (l *Limiter) Check(ctx context.Context, group, client string) (result Result, ok bool, err error) {
+	return l.run(ctx, group, client, false)
+}
+
+func (l *Limiter) run(ctx context.Context, group, client string, record bool) (result Result, ok bool, err error) {
+	policy := l.policies[group]
+	if policy.Limit <= 0 {
+		return Result{}, false, nil
+	}
+
+	recordArg := 0
+	if record {
+		recordArg = 1
+	}
+	key := "ratelimit:" + group + ":" + client
+	values, err := slidingWindow.Run(ctx, l.client, []string{key}, policy.Limit, policy.Window.Microseconds(), recordArg).Int64Slice()
+	if err != nil {
+		return Result{}, true, fmt.Errorf("failed to check rate limit: %v", err)
+	}
+
+	result = Result{
+		Allowed:   values[0] == 1,
+		Limit:     policy.Limit,
+		Remaining: int(values[1]),
+		Reset:     time.Duration(values[2]) * time.Microsecond,
+	}
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(values[3]) * time.Microsecond
+	}
+	return result, true, nil
+}
+
+// Policy returns the policy of group.
+func (l *Limiter) Policy(group string) Policy {
+	return l.policies[group]
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Policy
+	}{
+		{value: "", want: Policy{}},
+		{value: "100/1m", want: Policy{Limit: 100, Window: time.Minute}},
+		{value: "5/30s", want: Policy{Limit: 5, Window: 30 * time.Second}},
+		{value: "1/1h30m", want: Policy{Limit: 1, Window: 90 * time.Minute}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.value)
+		if err != nil {
+			t.Errorf("ParsePolicy(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	for _, value := range []string{"100", "/1m", "ten/1m", "0/1m", "-5/1m", "10/", "10/soon", "10/0s", "10/-1m"} {
+		if policy, err := ParsePolicy(value); err == nil {
+			t.Errorf("ParsePolicy(%q) = %+v, want an error", value, policy)
+		}
+	}
+}
+
+// newLimiter returns a limiter backed by an in-memory Redis whose clock the
+// test controls.
+func newLimiter(t *testing.T, policies map[string]Policy) (*Limiter, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewLimiter(client, policies), server
+}
+
+func TestAllowSlidingWindow(t *testing.T) {
+	limiter, server := newLimiter(t, map[string]Policy{"read": {Limit: 3, Window: time.Minute}})
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	allow := func(offset time.Duration) Result {
+		t.Helper()
+		server.SetTime(start.Add(offset))
+		result, ok, err := limiter.Allow(ctx, "read", "ip:10.0.0.1")
+		if err != nil || !ok {
+			t.Fatalf("Allow at %v: ok = %v, err = %v", offset, ok, err)
+		}
+		return result
+	}
+
+	for i, offset := range []time.Duration{0, 10 * time.Second, 20 * time.Second} {
+		result := allow(offset)
+		if !result.Allowed {
+			t.Fatalf("request %d rejected, want allowed", i+1)
+		}
+		if want := 2 - i; result.Remaining != want {
+			t.Errorf("request %d: Remaining = %d, want %d", i+1, result.Remaining, want)
+		}
+		if result.Limit != 3 {
+			t.Errorf("request %d: Limit = %d, want 3", i+1, result.Limit)
+		}
+	}
+	if result := allow(20 * time.Second); result.Reset != time.Minute {
+		t.Errorf("Reset = %v, want %v, when the newest request leaves the window", result.Reset, time.Minute)
+	}
+
+	// The window is full until the first request slides out of it.
+	result := allow(30 * time.Second)
+	if result.Allowed {
+		t.Fatal("fourth request allowed, want rejected")
+	}
+	if result.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", result.Remaining)
+	}
+	if want := 30 * time.Second; result.RetryAfter != want {
+		t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, want)
+	}
+
+	// Rejected requests are not recorded, so only the first one has expired here.
+	result = allow(time.Minute + time.Second)
+	if !result.Allowed {
+		t.Fatal("request after the oldest one expired was rejected")
+	}
+	if result.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", result.Remaining)
+	}
+}
+
+func TestAllowSeparatesClientsAndGroups(t *testing.T) {
+	limiter, _ := newLimiter(t, map[string]Policy{
+		"auth":  {Limit: 1, Window: time.Minute},
+		"write": {Limit: 1, Window: time.Minute},
+	})
+	ctx := context.Background()
+
+	for _, request := range []struct{ group, client string }{
+		{"auth", "ip:10.0.0.1"},
+		{"auth", "ip:10.0.0.2"},
+		{"write", "ip:10.0.0.1"},
+	} {
+		result, _, err := limiter.Allow(ctx, request.group, request.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Allowed {
+			t.Errorf("%s request by %s rejected, want its own window", request.group, request.client)
+		}
+	}
+	if result, _, _ := limiter.Allow(ctx, "auth", "ip:10.0.0.1"); result.Allowed {
+		t.Error("second auth request by 10.0.0.1 allowed, want rejected")
+	}
+}
+
+func TestAllowUnlimitedGroup(t *testing.T) {
+	limiter, server := newLimiter(t, map[string]Policy{"search": {}})
+	_, ok, err := limiter.Allow(context.Background(), "search", "ip:10.0.0.1")
+	if err != nil || ok {
+		t.Errorf("Allow on an unlimited group: ok = %v, err = %v, want false and nil", ok, err)
+	}
+	if _, ok, _ := limiter.Allow(context.Background(), "unknown", "ip:10.0.0.1"); ok {
+		t.Error("Allow on a group without a policy reported it limited")
+	}
+	if keys := server.Keys(); len(keys) != 0 {
+		t.Errorf("keys = %v, want none for unlimited groups", keys)
+	}
+}
+
+func TestCheckDoesNotRecord(t *testing.T) {
+	limiter, server := newLimiter(t, map[string]Policy{"auth": {Limit: 2, Window: time.Minute}})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		result, ok, err := limiter.Check(ctx, "auth", "ip:10.0.0.1")
+		if err != nil || !ok {
+			t.Fatalf("Check: ok = %v, err = %v", ok, err)
+		}
+		if !result.Allowed || result.Remaining != 2 {
+			t.Fatalf("Check %d = %+v, want allowed with 2 remaining", i+1, result)
+		}
+	}
+	if server.Exists("ratelimit:auth:ip:10.0.0.1") {
+		t.Error("Check created the window, want it left alone")
+	}
+
+	limiter.Allow(ctx, "auth", "ip:10.0.0.1")
+	limiter.Allow(ctx, "auth", "ip:10.0.0.1")
+	result, _, err := limiter.Check(ctx, "auth", "ip:10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed || result.RetryAfter != time.Minute {
+		t.Errorf("Check after the limit = %+v, want rejected with RetryAfter %v", result, time.Minute)
+	}
+}
+
+func TestWindowExpires(t *testing.T) {
+	limiter, server := newLimiter(t, map[string]Policy{"write": {Limit: 5, Window: time.Minute}})
+	if _, _, err := limiter.Allow(context.Background(), "write", "user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := server.TTL("ratelimit:write:user:1"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want the window, so idle clients leave no keys behind", ttl)
+	}
+}
@@ -0,0 +1,122 @@
+package songinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// releaseDateLayout is the date format used by the external info API (e.g. "16.07.2006").
+const releaseDateLayout = "02.01.2006"
+
+// ErrNotFound is returned when the external API has no details for the requested song.
+var ErrNotFound = errors.New("song details not found")
+
+// SongDetail holds the details the external API knows about a song.
+type SongDetail struct {
+	ReleaseDate time.Time
+	Text        string
+	Link        string
+}
+
+type songDetailResponse struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// Client fetches song details from the external info API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewClient creates a client for the API at baseURL. Every attempt is bounded by timeout,
+// and failed attempts are retried up to maxRetries times with exponential backoff.
+func NewClient(baseURL string, timeout time.Duration, maxRetries int, backoff time.Duration) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+}
+
+// GetSongDetail calls GET <baseURL>?group=<group>&song=<song>.
+func (c *Client) GetSongDetail(ctx context.Context, group, song string) (*SongDetail, error) {
+	reqURL, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid external API url: %v", err)
+	}
+	query := reqURL.Query()
+	query.Set("group", group)
+	query.Set("song", song)
+	reqURL.RawQuery = query.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := c.backoff << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		detail, retry, err := c.fetch(ctx, reqURL.String())
+		if err == nil {
+			return detail, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("external API failed after %d attempts: %v", c.maxRetries+1, lastErr)
+}
+
+// fetch performs a single request and reports whether a failure is worth retrying.
+func (c *Client) fetch(ctx context.Context, reqURL string) (*SongDetail, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("unexpected status: %s", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var body songDetailResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	detail := &SongDetail{Text: body.Text, Link: body.Link}
+	if body.ReleaseDate != "" {
+		releaseDate, err := time.Parse(releaseDateLayout, body.ReleaseDate)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid release date %q: %v", body.ReleaseDate, err)
+		}
+		detail.ReleaseDate = releaseDate
+	}
+	return detail, false, nil
+}
@@ -0,0 +1,157 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newServer starts a stand-in for the info API that answers with the given
+// handler and counts the requests it receives.
+func newServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, attempt int)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, int(calls.Add(1)))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestGetSongDetail(t *testing.T) {
+	server, _ := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int) {
+		if got := r.URL.Query().Get("group"); got != "Muse" {
+			t.Errorf("group = %q, want Muse", got)
+		}
+		if got := r.URL.Query().Get("song"); got != "Supermassive Black Hole" {
+			t.Errorf("song = %q, want Supermassive Black Hole", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"releaseDate": "16.07.2006", "text": "Ooh baby, don't you know I suffer?", "link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"}`))
+	})
+
+	client := NewClient(server.URL, time.Second, 0, time.Millisecond)
+	detail, err := client.GetSongDetail(context.Background(), "Muse", "Supermassive Black Hole")
+	if err != nil {
+		t.Fatalf("GetSongDetail: %v", err)
+	}
+	if want := time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC); !detail.ReleaseDate.Equal(want) {
+		t.Errorf("ReleaseDate = %v, want %v", detail.ReleaseDate, want)
+	}
+	if want := "Ooh baby, don't you know I suffer?"; detail.Text != want {
+		t.Errorf("Text = %q, want %q", detail.Text, want)
+	}
+	if want := "https://www.youtube.com/watch?v=Xsp3_a-PMTw"; detail.Link != want {
+		t.Errorf("Link = %q, want %q", detail.Link, want)
+	}
+}
+
+func TestGetSongDetailNotFound(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	client := NewClient(server.URL, time.Second, 3, time.Millisecond)
+	_, err := client.GetSongDetail(context.Background(), "Muse", "Unknown")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1; 404 is not retried", got)
+	}
+}
+
+func TestGetSongDetailRetries(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var times []time.Time
+			server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, attempt int) {
+				times = append(times, time.Now())
+				if attempt < 3 {
+					w.WriteHeader(status)
+					return
+				}
+				w.Write([]byte(`{"text": "lyrics"}`))
+			})
+
+			backoff := 20 * time.Millisecond
+			client := NewClient(server.URL, time.Second, 3, backoff)
+			detail, err := client.GetSongDetail(context.Background(), "Muse", "Uprising")
+			if err != nil {
+				t.Fatalf("GetSongDetail: %v", err)
+			}
+			if detail.Text != "lyrics" {
+				t.Errorf("Text = %q, want lyrics", detail.Text)
+			}
+			if got := calls.Load(); got != 3 {
+				t.Fatalf("requests = %d, want 3", got)
+			}
+			// The wait doubles after every failed attempt.
+			if wait := times[1].Sub(times[0]); wait < backoff {
+				t.Errorf("first retry after %v, want at least %v", wait, backoff)
+			}
+			if wait := times[2].Sub(times[1]); wait < 2*backoff {
+				t.Errorf("second retry after %v, want at least %v", wait, 2*backoff)
+			}
+		})
+	}
+}
+
+func TestGetSongDetailGivesUp(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client := NewClient(server.URL, time.Second, 2, time.Millisecond)
+	_, err := client.GetSongDetail(context.Background(), "Muse", "Uprising")
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("err = %v, want failure after 3 attempts", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestGetSongDetailTimeout(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	server, calls := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	client := NewClient(server.URL, 20*time.Millisecond, 1, time.Millisecond)
+	start := time.Now()
+	_, err := client.GetSongDetail(context.Background(), "Muse", "Uprising")
+	if err == nil {
+		t.Fatal("GetSongDetail succeeded, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %v, want the client timeout to bound each attempt", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2; timeouts are retried", got)
+	}
+}
+
+func TestGetSongDetailMalformedReleaseDate(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.Write([]byte(`{"releaseDate": "2006-07-16", "text": "lyrics"}`))
+	})
+
+	client := NewClient(server.URL, time.Second, 3, time.Millisecond)
+	_, err := client.GetSongDetail(context.Background(), "Muse", "Uprising")
+	if err == nil || !strings.Contains(err.Error(), "invalid release date") {
+		t.Fatalf("err = %v, want an invalid release date error", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1; a malformed response is not retried", got)
+	}
+}
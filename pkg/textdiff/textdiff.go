@@ -0,0 +1,174 @@
+package textdiff
+
+import (
+	"slices"
+	"strings"
+)
+
+// Line operations.
+const (
+	Equal  = " "
+	Insert = "+"
+	Delete = "-"
+)
+
+// Line is one line of a diff: unchanged, inserted or deleted.
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// SplitLines splits text into lines, accepting both \n and \r\n endings.
+func SplitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// maxCompared bounds the line comparisons of a diff. Once the changed parts
+// of two texts are too long to compare every line of one with every line of
+// the other, the changed block is shown as deleted and inserted whole, which
+// is correct but not minimal.
+const maxCompared = 1 << 24
+
+// Lines returns a minimal line diff turning a into b, based on their longest
+// common subsequence. It uses space linear in the length of the texts.
+func Lines(a, b []string) []Line {
+	// Common prefix and suffix are trimmed first; edits are usually local.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		result = append(result, Line{Op: Equal, Text: text})
+	}
+	changedA, changedB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(changedA)*len(changedB) > maxCompared {
+		result = replace(result, changedA, changedB)
+	} else {
+		d := newDiff(changedA, changedB)
+		result = d.diff(result, 0, len(changedA), 0, len(changedB))
+	}
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: Equal, Text: text})
+	}
+	return result
+}
+
+// replace appends a as deleted and b as inserted.
+func replace(result []Line, a, b []string) []Line {
+	for _, text := range a {
+		result = append(result, Line{Op: Delete, Text: text})
+	}
+	for _, text := range b {
+		result = append(result, Line{Op: Insert, Text: text})
+	}
+	return result
+}
+
+// diff finds the longest common subsequence with Hirschberg's algorithm,
+// which keeps only two rows of LCS lengths at a time. Lines are numbered so
+// comparing them is cheap.
+type diff struct {
+	a, b              []string
+	ids               [2][]int
+	forward, backward []int
+	previous          []int
+}
+
+func newDiff(a, b []string) *diff {
+	numbers := map[string]int{}
+	number := func(lines []string) []int {
+		ids := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := numbers[line]
+			if !ok {
+				id = len(numbers)
+				numbers[line] = id
+			}
+			ids[i] = id
+		}
+		return ids
+	}
+	return &diff{
+		a:        a,
+		b:        b,
+		ids:      [2][]int{number(a), number(b)},
+		forward:  make([]int, len(b)+1),
+		backward: make([]int, len(b)+1),
+		previous: make([]int, len(b)+1),
+	}
+}
+
+// diff appends the diff of a[aLow:aHigh] and b[bLow:bHigh].
+func (d *diff) diff(result []Line, aLow, aHigh, bLow, bHigh int) []Line {
+	a, b := d.ids[0], d.ids[1]
+	switch {
+	case aLow == aHigh || bLow == bHigh:
+		return replace(result, d.a[aLow:aHigh], d.b[bLow:bHigh])
+	case aHigh-aLow == 1:
+		for j := bLow; j < bHigh; j++ {
+			if a[aLow] == b[j] {
+				result = replace(result, nil, d.b[bLow:j])
+				result = append(result, Line{Op: Equal, Text: d.a[aLow]})
+				return replace(result, nil, d.b[j+1:bHigh])
+			}
+		}
+		return replace(result, d.a[aLow:aHigh], d.b[bLow:bHigh])
+	}
+
+	// Split a in half and b where the LCS lengths of the two halves add up
+	// to the most: some longest common subsequence passes through there.
+	mid := (aLow + aHigh) / 2
+	d.lengths(d.forward, aLow, mid, bLow, bHigh, false)
+	d.lengths(d.backward, mid, aHigh, bLow, bHigh, true)
+	split, best := bLow, -1
+	for j := bLow; j <= bHigh; j++ {
+		if total := d.forward[j-bLow] + d.backward[j-bLow]; total > best {
+			split, best = j, total
+		}
+	}
+	result = d.diff(result, aLow, mid, bLow, split)
+	return d.diff(result, mid, aHigh, split, bHigh)
+}
+
+// lengths fills row[j-bLow] with the LCS length of a[aLow:aHigh] and
+// b[bLow:j], or of a[aLow:aHigh] and b[j:bHigh] when reverse is set.
+func (d *diff) lengths(row []int, aLow, aHigh, bLow, bHigh int, reverse bool) {
+	a, b := d.ids[0], d.ids[1]
+	n := bHigh - bLow
+	current, previous := row[:n+1], d.previous[:n+1]
+	clear(current)
+	for i := aLow; i < aHigh; i++ {
+		current, previous = previous, current
+		ai := a[i]
+		if reverse {
+			ai = a[aHigh-1-(i-aLow)]
+		}
+		current[0] = 0
+		for k := 1; k <= n; k++ {
+			bj := b[bLow+k-1]
+			if reverse {
+				bj = b[bHigh-k]
+			}
+			if ai == bj {
+				current[k] = previous[k-1] + 1
+			} else {
+				current[k] = max(previous[k], current[k-1])
+			}
+		}
+	}
+	if &current[0] != &row[0] {
+		copy(row, current)
+	}
+	if reverse {
+		slices.Reverse(row[:n+1])
+	}
+}
@@ -0,0 +1,148 @@
+package textdiff
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// sides rebuilds the two texts a diff compares.
+func sides(diff []Line) (a, b []string) {
+	a, b = []string{}, []string{}
+	for _, line := range diff {
+		if line.Op != Insert {
+			a = append(a, line.Text)
+		}
+		if line.Op != Delete {
+			b = append(b, line.Text)
+		}
+	}
+	return a, b
+}
+
+// lcsLength is the textbook quadratic LCS, to check diffs are minimal.
+func lcsLength(a, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				lengths[i][j] = lengths[i-1][j-1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i-1][j], lengths[i][j-1])
+			}
+		}
+	}
+	return lengths[len(a)][len(b)]
+}
+
+func equalLines(diff []Line) int {
+	n := 0
+	for _, line := range diff {
+		if line.Op == Equal {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{name: "both empty", a: "", b: "", want: []Line{}},
+		{name: "added", a: "", b: "one\ntwo", want: []Line{{Insert, "one"}, {Insert, "two"}}},
+		{name: "removed", a: "one\ntwo", b: "", want: []Line{{Delete, "one"}, {Delete, "two"}}},
+		{
+			name: "changed line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []Line{{Equal, "one"}, {Delete, "two"}, {Insert, "2"}, {Equal, "three"}},
+		},
+		{
+			name: "moved line",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\na\nd",
+			want: []Line{{Delete, "a"}, {Equal, "b"}, {Equal, "c"}, {Insert, "a"}, {Equal, "d"}},
+		},
+		{
+			name: "CRLF matches LF",
+			a:    "one\r\ntwo\r\n",
+			b:    "one\ntwo\nthree\n",
+			want: []Line{{Equal, "one"}, {Equal, "two"}, {Insert, "three"}, {Equal, ""}},
+		},
+	}
+	for _, tt := range tests {
+		got := Lines(SplitLines(tt.a), SplitLines(tt.b))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Lines = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinesIsMinimal(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	text := func() []string {
+		lines := make([]string, random.Intn(30))
+		for i := range lines {
+			lines[i] = strconv.Itoa(random.Intn(5))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := text(), text()
+		diff := Lines(a, b)
+		gotA, gotB := sides(diff)
+		if !reflect.DeepEqual(gotA, a) || !reflect.DeepEqual(gotB, b) {
+			t.Fatalf("diff of %q and %q = %v, which does not rebuild them", a, b, diff)
+		}
+		if got, want := equalLines(diff), lcsLength(a, b); got != want {
+			t.Fatalf("diff of %q and %q keeps %d lines, want %d", a, b, got, want)
+		}
+	}
+}
+
+func TestLinesLargeChange(t *testing.T) {
+	// Too many lines to compare pairwise: the changed block is replaced whole,
+	// between the common prefix and suffix.
+	n := 5000
+	a, b := make([]string, n), make([]string, n)
+	for i := range a {
+		a[i] = "old " + strconv.Itoa(i)
+		b[i] = "new " + strconv.Itoa(i)
+	}
+	a = append([]string{"first"}, append(a, "last")...)
+	b = append([]string{"first"}, append(b, "last")...)
+
+	diff := Lines(a, b)
+	if len(diff) != 2*n+2 {
+		t.Fatalf("diff has %d lines, want %d", len(diff), 2*n+2)
+	}
+	if diff[0] != (Line{Equal, "first"}) || diff[len(diff)-1] != (Line{Equal, "last"}) {
+		t.Errorf("diff ends = %v, %v, want the common lines", diff[0], diff[len(diff)-1])
+	}
+	if diff[1].Op != Delete || diff[n+1].Op != Insert {
+		t.Errorf("diff = %v ... %v, want the deletions before the insertions", diff[1], diff[n+1])
+	}
+	if gotA, gotB := sides(diff); !reflect.DeepEqual(gotA, a) || !reflect.DeepEqual(gotB, b) {
+		t.Error("diff does not rebuild the texts")
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	if got := SplitLines(""); len(got) != 0 {
+		t.Errorf("SplitLines(\"\") = %q, want none", got)
+	}
+	if got, want := SplitLines("a\r\nb\nc"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitLines = %q, want %q", got, want)
+	}
+	if got := SplitLines(strings.Repeat("x\n", 3)); len(got) != 4 {
+		t.Errorf("SplitLines = %q, want a trailing empty line", got)
+	}
+}